@@ -0,0 +1,10 @@
+module github.com/armatys/android-tools
+
+go 1.26.0
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	golang.org/x/text v0.42.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
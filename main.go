@@ -5,9 +5,42 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"github.com/armatys/android-tools/strings/apierr"
+	"github.com/armatys/android-tools/strings/apk"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/brands"
+	"github.com/armatys/android-tools/strings/budget"
+	"github.com/armatys/android-tools/strings/coverage"
 	"github.com/armatys/android-tools/strings/crowdin"
+	"github.com/armatys/android-tools/strings/diff"
+	"github.com/armatys/android-tools/strings/glossary"
+	"github.com/armatys/android-tools/strings/history"
+	"github.com/armatys/android-tools/strings/issues"
+	"github.com/armatys/android-tools/strings/locales"
+	"github.com/armatys/android-tools/strings/lock"
+	"github.com/armatys/android-tools/strings/onboard"
+	"github.com/armatys/android-tools/strings/pipeline"
+	"github.com/armatys/android-tools/strings/project"
+	"github.com/armatys/android-tools/strings/pseudo"
+	"github.com/armatys/android-tools/strings/releasenotes"
+	"github.com/armatys/android-tools/strings/report"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/server"
+	"github.com/armatys/android-tools/strings/triage"
+	"github.com/armatys/android-tools/strings/usage"
 	"github.com/armatys/android-tools/strings/validator"
+	"github.com/fsnotify/fsnotify"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 )
 
 // The action name to perform.
@@ -16,6 +49,9 @@ var actionNameArg string
 // The path to the Android's "res" directory.
 var projectResDirArg string
 
+// The path to the root of a Gradle multi-module project, whose modules are discovered and validated.
+var projectDirArg string
+
 // The base locale used for comparison and validation of other locale strings.
 var baseLocaleArg string
 
@@ -25,114 +61,1988 @@ var stringsFileNameArg string
 // Flag that specifies it the string validator should show strings that exist in base resources, but not in other resources.
 var showMissingArg bool
 
+// The time budget for the validation (zero means no limit).
+var deadlineArg time.Duration
+
+// The lowest severity of the findings that fail the validation.
+var failOnArg string
+
+// The maximum number of warnings accepted by the validation (no limit if negative).
+var maxWarningsArg int
+
+// The maximum number of errors accepted by the validation (no limit if negative).
+var maxErrorsArg int
+
+// If true, missing translations fail the validation regardless of their severity and the thresholds.
+var failOnMissingArg bool
+
+// The path to an APK file to import the translations from.
+var apkFileArg string
+
+// The path to a JSON file with the brand variables.
+var brandsFileArg string
+
+// The name of the brand to expand (all brands are expanded if empty).
+var brandArg string
+
+// The output directory.
+var outDirArg string
+
+// The git revision to compare from.
+var fromRefArg string
+
+// The git revision to compare to (the working tree if empty).
+var toRefArg string
+
+// Path to a JSON file with the configuration of the issue filing for the persistent findings.
+var issuesConfigFileArg string
+
+// The name of the string whose history is shown.
+var keyArg string
+
+// The output format (the default format of the action if empty).
+var formatArg string
+
+// Flag that specifies if the fixable problems should be fixed in the files before the validation.
+var fixArg bool
+
+// If true, the translations of the resources not declared in the base resources are deleted before the validation.
+var deleteOrphansArg bool
+
+// Flag that specifies if the missing translations should be reported with a translation of a similar string.
+var suggestArg bool
+
+// If true, the approved suggested translations are written to the strings files.
+var fillArg bool
+
+// The path to the JSON file, to which the suggested translations needing a review are written.
+var reviewFileArg string
+
+// The path to the output file (the standard output if empty).
+var outputFileArg string
+
+// The language of the locale display names in the reports (no display names if empty).
+var displayLanguageArg string
+
+// Path to a JSON file with the validator configuration.
+var configFileArg string
+
+// The name of the configuration profile to use.
+var profileArg string
+
+// The comma-separated locales to validate, overriding the ones in the configuration file.
+var localesArg string
+
+// The comma-separated locales not to validate, overriding the ones in the configuration file.
+var excludeLocalesArg string
+
+// The comma-separated glob patterns of the names of the resources to validate (all resources if empty).
+var keysArg string
+
+// Path to a JSON file with the provider pipelines configuration.
+var pipelineConfigFileArg string
+
+// The name of the only pipeline to run (all pipelines are run if empty).
+var pipelineOnlyArg string
+
+// The path to the JSONL audit log of the changed files (no log if empty).
+var auditLogArg string
+
+// Flag that specifies if the project lock held by another process should be overridden.
+var forceArg bool
+
+// Comma-separated form-factor overlay modules, like "car=car/src/main/res".
+var overlaysArg string
+
+// How the values directories with configuration qualifiers (e.g. "values-night") are validated.
+var qualifiedDirsArg string
+
+// Flag that specifies if only the strings files and base strings changed since the -from revision should be validated.
+var changedOnlyArg bool
+
+// If true, the strings are validated again whenever a strings file changes, until the process is interrupted.
+var watchArg bool
+
+// If true, the findings are reviewed interactively, and the ignored ones are added to the -baseline file.
+var triageArg bool
+
+// The git revision whose base strings are compared with the current ones, to find the translations not updated since.
+var sinceArg string
+
+// Flag that specifies if a summary line with the number of findings and the exit code should be printed to the standard error.
+var summaryArg bool
+
+// The path to the baseline file with the accepted findings; it is created with the current findings if it does not exist.
+var baselineFileArg string
+
+// Path to the JSON file with the project glossary.
+var glossaryFileArg string
+
+// The locale added by the 'onboard-locale' action, e.g. "th" or "pt-rBR".
+var localeArg string
+
+// The locale the -locale is compared against by the 'diff' action; the base locale if empty.
+var againstArg string
+
+// The address on which the dashboard is served.
+var listenArg string
+
+// The project locks held by the current process, keyed by the lock file path.
+var projectLocks = make(map[string]*lock.Lock)
+
 // Path to a file with configuration for accessing crowdin.
 // The file should contain a JSON object like this: {"Key": "api_key", "ProjectName": "the-project-name"}
 var crowdinConfigFileArg string
 
+// The path to the base app module directory.
+var baseModuleDirArg string
+
+// Comma-separated paths to the dynamic feature module directories.
+var featureModuleDirsArg string
+
+// The path to the resource shrinker keep rules file (e.g. "res/raw/keep.xml").
+var keepRulesFileArg string
+
+// The path to a file with names (or glob patterns) of strings that are looked up dynamically, one per line.
+var dynamicKeysFileArg string
+
+// The path to the source code directory, scanned for dynamic string lookups.
+var srcDirArg string
+
+// If true, the unused strings are deleted from the strings files of all locales.
+var deleteArg bool
+
+// The name of the file the strings files of each values directory are merged into.
+var mergeIntoArg string
+
+// The order of the resources after sorting: "base" (the order of the base strings file) or "name" (alphabetical).
+var sortByArg string
+
+// The exit code used when the validation found errors.
+const exitCodeFailure = 1
+
+// The exit code used when the tool is used incorrectly, e.g. with a missing or invalid flag.
+const exitCodeUsage = 2
+
+// The exit code used when a file cannot be read, written or parsed, or a request to a provider fails.
+const exitCodeError = 3
+
+// The exit code used when the validation did not finish before the deadline.
+const exitCodeDeadlineExceeded = 124
+
 var (
 	actionNameValidate      = "validate"
 	actionNameCrowdinUpdate = "crowdin-update"
 	actionNameCrowdinExport = "crowdin-export"
-	supportedActionNames    = []string{actionNameValidate, actionNameCrowdinUpdate, actionNameCrowdinExport}
+	actionNameFeatures      = "feature-isolation"
+	actionNameShrinkReport  = "shrink-report"
+	actionNameApkImport     = "apk-import"
+	actionNameBrandExpand   = "brand-expand"
+	actionNameReleaseNotes  = "release-notes"
+	actionNamePull          = "pull"
+	actionNamePush          = "push"
+	actionNameHistory       = "history"
+	actionNameServe         = "serve"
+	actionNameOnboard       = "onboard-locale"
+	actionNamePseudo        = "pseudo"
+	actionNameCoverage      = "coverage"
+	actionNameUnused        = "unused"
+	actionNameFormat        = "format"
+	actionNameSort          = "sort"
+	actionNameMerge         = "merge"
+	actionNameDiff          = "diff"
+	actionNameFix           = "fix"
+	supportedActionNames    = []string{actionNameValidate, actionNamePull, actionNamePush, actionNameCrowdinUpdate, actionNameCrowdinExport, actionNameFeatures, actionNameShrinkReport, actionNameApkImport, actionNameBrandExpand, actionNameReleaseNotes, actionNameHistory, actionNameServe, actionNameOnboard, actionNamePseudo, actionNameCoverage, actionNameUnused, actionNameFormat, actionNameSort, actionNameMerge, actionNameDiff, actionNameFix}
 )
 
 func init() {
-	flag.StringVar(&actionNameArg, "action", actionNameValidate, fmt.Sprintf("Action to perform, one of %v.", supportedActionNames))
-	flag.StringVar(&projectResDirArg, "resdir", "", "The path to the 'res' directory of your Android project (required for 'validate' and 'crowdin-update').")
+	flag.StringVar(&actionNameArg, "action", actionNameValidate, fmt.Sprintf("Deprecated, use the commands instead. Action to perform, one of %v.", supportedActionNames))
+	flag.StringVar(&projectResDirArg, "resdir", "", "The path to the 'res' directory of your Android project (required for 'validate', 'crowdin-update' and 'apk-import').")
+	flag.StringVar(&projectDirArg, "project", "", "The path to the root of a Gradle multi-module project; the 'src/*/res' directories of every module are validated instead of the -resdir (use with 'validate').")
 	flag.StringVar(&baseLocaleArg, "baselocale", "", "The base locale used for validation of other locale strings (e.g. 'en' or 'en-rGB').")
-	flag.StringVar(&stringsFileNameArg, "filename", "strings.xml", "The name of the xml file with XML string resources (required for 'validate' and 'crowdin-update').")
+	flag.StringVar(&stringsFileNameArg, "filename", "strings.xml", "The name of the xml file with XML string resources (required for 'validate' and 'crowdin-update'). For 'validate' and 'serve' it can also be a comma-separated list or a glob pattern, e.g. 'strings.xml,plurals.xml' or 'strings*.xml'; the files of each values directory are validated as one set.")
 	flag.BoolVar(&showMissingArg, "missing", false, "If true shows the missing translations (use with 'validate').")
-	flag.StringVar(&crowdinConfigFileArg, "crowdin-conf", "", "The path to a file with a JSON configuration for accessing Crowdin service (required for 'crowdin-*'). The JSON should look like {\"Key\": \"api_key\", \"ProjectName\": \"the-project-name\"}")
+	flag.BoolVar(&fixArg, "fix", false, "If true, replaces the iOS format specifiers (e.g. '%@') with the Android ones, and normalizes the files to the Unicode NFC form before the validation (use with 'validate').")
+	flag.BoolVar(&deleteOrphansArg, "delete-orphans", false, "If true, deletes the strings, plurals and arrays which are not declared in the base resources from the other locales before the validation (use with 'validate').")
+	flag.BoolVar(&suggestArg, "suggest", false, "If true, the missing translations are reported with a suggested translation of the most similar translated string (use with 'validate -missing').")
+	flag.BoolVar(&fillArg, "fill", false, "If true, the suggested translations with a confidence of at least the 'AutoApproveConfidence' of the configuration are written to the strings files as final, and the others are listed as needing review (use with 'validate -missing -suggest').")
+	flag.StringVar(&reviewFileArg, "review-file", "", "The path to a JSON file, to which the suggested translations needing review are written, with their confidence and the reasons (use with 'validate -fill').")
+	flag.DurationVar(&deadlineArg, "deadline", 0, fmt.Sprintf("The time budget for the validation, e.g. '30s'; the locales not checked in time are reported and the exit code is %d (use with 'validate').", exitCodeDeadlineExceeded))
+	flag.StringVar(&failOnArg, "fail-on", validator.SeverityError, "The lowest severity of the findings that fail the validation: 'error', 'warning' or 'info'.")
+	flag.IntVar(&maxWarningsArg, "max-warnings", -1, "The maximum number of warnings accepted by the validation; no limit if negative.")
+	flag.IntVar(&maxErrorsArg, "max-errors", -1, "The maximum number of errors accepted by the validation; any error fails it if negative.")
+	flag.BoolVar(&failOnMissingArg, "fail-on-missing", false, "Fail the validation on any missing translation, regardless of its severity and the thresholds (implies -missing).")
+	flag.StringVar(&apkFileArg, "apk", "", "The path to an APK file, whose translations are imported into the missing translations of the project (required for 'apk-import').")
+	flag.StringVar(&brandsFileArg, "brands", "", "The path to a JSON file with the brand variables, e.g. {\"acme\": {\"app_name\": \"Acme\"}} (required for 'brand-expand', optional for 'validate').")
+	flag.StringVar(&brandArg, "brand", "", "The name of the brand to expand; all brands are expanded if empty (use with 'brand-expand').")
+	flag.StringVar(&outDirArg, "outdir", "", "The output directory; for 'brand-expand' a 'res' directory is created there for each brand, for 'pseudo' the res directory is used if empty.")
+	flag.StringVar(&fromRefArg, "from", "", "The git revision to compare from (required for 'release-notes'; use with 'validate -changed-only').")
+	flag.StringVar(&toRefArg, "to", "", "The git revision to compare to; the working tree is used if empty (use with 'release-notes').")
+	flag.StringVar(&issuesConfigFileArg, "issues-conf", "", "The path to a JSON file configuring the issues opened for the persistent findings, e.g. {\"Tracker\": \"github\", \"Project\": \"owner/repo\", \"Token\": \"...\", \"MinRuns\": 3}; the token can be set with the GITHUB_TOKEN, GITLAB_TOKEN or JIRA_API_TOKEN environment variable instead (use with 'validate').")
+	flag.StringVar(&keyArg, "key", "", "The name of the string whose changes are shown (required for 'history').")
+	flag.StringVar(&formatArg, "format", "", "The output format: 'markdown' (the default) or 'xlsx' for 'release-notes'; 'json', 'html', 'sarif' or 'checkstyle' for 'validate' (the default is a plain text); 'json' for 'coverage' and 'diff' (the default is a table for 'coverage' and a plain text for 'diff').")
+	flag.StringVar(&outputFileArg, "output", "", "The path to the output file; the standard output is used if empty (required for the 'xlsx' format).")
+	flag.StringVar(&displayLanguageArg, "display-language", locales.DefaultDisplayLanguage, "The language of the locale names shown in the reports, e.g. 'en' or 'de'; the names are not shown if empty.")
+	flag.StringVar(&configFileArg, "config", "", "The path to a JSON file with the validator configuration, e.g. {\"Rules\": {\"markup\": false}, \"Locales\": [\"de\"], \"Profiles\": {\"release\": {...}}} (use with 'validate').")
+	flag.StringVar(&localesArg, "locales", "", "The comma-separated locales to validate, e.g. 'de,fr,pt-rBR'; all locales are validated if empty (use with 'validate').")
+	flag.StringVar(&excludeLocalesArg, "exclude-locales", "", "The comma-separated locales not to validate, e.g. 'ar,he' (use with 'validate').")
+	flag.StringVar(&keysArg, "keys", "", "The comma-separated glob patterns of the names of the resources to validate, e.g. 'checkout_*' (use with 'validate').")
+	flag.StringVar(&profileArg, "profile", "", "The name of the profile to use from the validator configuration file and the pipelines configuration file, e.g. 'ci' or 'release' (use with 'validate', 'pull' and 'push').")
+	flag.StringVar(&baseModuleDirArg, "basemodule", "", "The path to the base app module directory (required for 'feature-isolation').")
+	flag.StringVar(&featureModuleDirsArg, "featuremodules", "", "Comma-separated paths to the dynamic feature module directories (required for 'feature-isolation').")
+	flag.StringVar(&keepRulesFileArg, "keep-rules", "", "The path to the resource shrinker keep rules file, e.g. 'res/raw/keep.xml' (use with 'shrink-report').")
+	flag.StringVar(&dynamicKeysFileArg, "dynamic-keys", "", "The path to a file listing names or glob patterns of strings looked up dynamically, one per line (use with 'shrink-report' and 'unused').")
+	flag.StringVar(&srcDirArg, "srcdir", "", "The path to the source code directory scanned for 'getIdentifier' lookups (use with 'shrink-report'), or for the string references (use with 'unused'; the parent of the -resdir if empty).")
+	flag.StringVar(&mergeIntoArg, "merge-into", "strings.xml", "The name of the file the strings files (a file set in -filename, e.g. 'strings.xml,legacy_strings.xml') of each values directory are merged into (use with 'merge').")
+	flag.StringVar(&sortByArg, "sort-by", "base", "The order of the sorted resources, 'base' (the order of the base strings file) or 'name' (use with 'sort').")
+	flag.BoolVar(&deleteArg, "delete", false, "If true, deletes the unused strings from the strings files of all locales (use with 'unused').")
+	flag.StringVar(&pipelineConfigFileArg, "pipeline-conf", "", "The path to a JSON file with the provider pipelines, e.g. {\"Pipelines\": [{\"Name\": \"staging\", \"Provider\": \"crowdin\", \"Crowdin\": {...}, \"ResDir\": \"app/src/main/res\"}], \"Profiles\": {\"local\": {\"Pipelines\": [{\"Name\": \"staging\", \"ResDir\": \"../app/res\"}]}}}; the Crowdin key can be set with the CROWDIN_API_KEY (or CROWDIN_API_KEY_STAGING for the 'staging' pipeline) environment variable instead (required for 'pull' and 'push').")
+	flag.StringVar(&pipelineOnlyArg, "pipeline-only", "", "The name of the only pipeline to run; all pipelines are run if empty (use with 'pull' and 'push').")
+	flag.StringVar(&auditLogArg, "audit-log", "", "The path to a JSONL file, to which every file created, modified or deleted by the action is appended, with the source and the content hashes.")
+	flag.BoolVar(&forceArg, "force", false, "If true, overrides the project lock held by another process (use with 'pull', 'push', 'crowdin-*' and 'apk-import').")
+	flag.StringVar(&overlaysArg, "overlays", "", "Comma-separated res directories of the form-factor overlay modules, like 'car=car/src/main/res' (use with 'validate'). The overlay directories like 'values-watch' are validated without it.")
+	flag.StringVar(&qualifiedDirsArg, "qualified-dirs", validator.QualifiersMerge, fmt.Sprintf("How the values directories with qualifiers other than the locale (e.g. 'values-night' or 'values-de-v21') are validated: '%s' compares them with the directories without the qualifiers, '%s' ignores them (use with 'validate' and 'serve').", validator.QualifiersMerge, validator.QualifiersSkip))
+	flag.BoolVar(&watchArg, "watch", false, "If true, watches the res directories and validates the strings again whenever a file changes, printing the new and the resolved findings, until interrupted (use with 'validate' and the plain text output).")
+	flag.BoolVar(&triageArg, "triage", false, "If true, lists the findings by the locale and the rule, and lets you review them one by one in the terminal and add the accepted ones to the -baseline file (use with 'validate').")
+	flag.StringVar(&sinceArg, "since", "", "The git revision, e.g. 'v2.1', since which the translations whose base text changed, but which were not updated, are reported as stale (use with 'validate').")
+	flag.BoolVar(&changedOnlyArg, "changed-only", false, "If true, only the strings files changed since the -from revision (HEAD if empty), and the changed base strings in all locales, are validated (use with 'validate').")
+	flag.BoolVar(&summaryArg, "summary", false, fmt.Sprintf("If true, prints a summary line with the number of findings and the exit code to the standard error, also with -format. The exit codes are: 0 if there are no failing findings, %d if there are, %d for a usage error and %d if a file cannot be read or parsed (use with 'validate').", exitCodeFailure, exitCodeUsage, exitCodeError))
+	flag.StringVar(&baselineFileArg, "baseline", "", "The path to a baseline file; the findings listed there are not reported. If the file does not exist, it is created with all the current findings (use with 'validate').")
+	flag.StringVar(&glossaryFileArg, "glossary", "", "The path to a JSON file with the approved translations of the project terms, like {\"Settings\": {\"de\": [\"Einstellungen\"]}} (use with 'validate').")
+	flag.StringVar(&localeArg, "locale", "", "The new locale, e.g. 'th' or 'pt-rBR' (required for 'onboard-locale'), or the only pseudo-locale to generate, 'en-rXA' or 'ar-rXB' (use with 'pseudo'), or the compared locale (required for 'diff').")
+	flag.StringVar(&againstArg, "against", "", "The locale the -locale is compared against, e.g. 'pt' for 'pt-rBR'; the base locale if empty (use with 'diff').")
+	flag.StringVar(&listenArg, "listen", "localhost:8080", "The address on which the dashboard is served (use with 'serve').")
+	flag.StringVar(&crowdinConfigFileArg, "crowdin-conf", "", "The path to a file with a JSON configuration for accessing Crowdin service (used by 'crowdin-*', which are superseded by 'pull' and 'push'). The JSON should look like {\"Key\": \"api_key\", \"ProjectName\": \"the-project-name\"}; the key and the project name can be set with the CROWDIN_API_KEY and CROWDIN_PROJECT environment variables instead.")
 }
 
 func main() {
-	flag.Parse()
+	flag.Usage = printUsage
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		parseCommand(os.Args[1:])
+	} else {
+		flag.Parse()
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "action" {
+				if c := commandOfAction(actionNameArg); c != nil {
+					fmt.Fprintf(os.Stderr, "The -action flag is deprecated, use the '%s %s' command instead.\n", programName(), c.name)
+				}
+			}
+		})
+	}
 	if !isActionSupported(actionNameArg) {
 		fmt.Printf("Action '%s' is not supported.\n", actionNameArg)
-		os.Exit(-1)
+		os.Exit(exitCodeUsage)
+	}
+	if len(auditLogArg) > 0 {
+		audit.Enable(auditLogArg, actionNameArg)
+	}
+	if !validator.IsSeverity(failOnArg) {
+		fmt.Printf("Severity '%s' is not supported.\n", failOnArg)
+		os.Exit(exitCodeUsage)
+	}
+	if qualifiedDirsArg != validator.QualifiersMerge && qualifiedDirsArg != validator.QualifiersSkip {
+		fmt.Printf("Qualified directories handling '%s' is not supported.\n", qualifiedDirsArg)
+		os.Exit(exitCodeUsage)
 	}
 	if actionNameArg == actionNameValidate {
 		validateStrings()
+	} else if actionNameArg == actionNamePull {
+		pull()
+	} else if actionNameArg == actionNamePush {
+		push()
 	} else if actionNameArg == actionNameCrowdinUpdate {
 		crowdinUpdate()
 	} else if actionNameArg == actionNameCrowdinExport {
 		crowdinExport()
+	} else if actionNameArg == actionNameFeatures {
+		validateFeatureIsolation()
+	} else if actionNameArg == actionNameShrinkReport {
+		shrinkReport()
+	} else if actionNameArg == actionNameApkImport {
+		apkImport()
+	} else if actionNameArg == actionNameBrandExpand {
+		brandExpand()
+	} else if actionNameArg == actionNameReleaseNotes {
+		releaseNotes()
+	} else if actionNameArg == actionNameHistory {
+		keyHistory()
+	} else if actionNameArg == actionNameServe {
+		serve()
+	} else if actionNameArg == actionNameOnboard {
+		onboardLocale()
+	} else if actionNameArg == actionNamePseudo {
+		pseudoLocales()
+	} else if actionNameArg == actionNameCoverage {
+		printCoverage()
+	} else if actionNameArg == actionNameUnused {
+		unusedStrings()
+	} else if actionNameArg == actionNameFormat {
+		formatStrings()
+	} else if actionNameArg == actionNameSort {
+		sortStrings()
+	} else if actionNameArg == actionNameMerge {
+		mergeStrings()
+	} else if actionNameArg == actionNameDiff {
+		diffLocales()
+	} else if actionNameArg == actionNameFix {
+		fixFiles()
 	}
 }
 
-func validateStrings() {
-	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
-		flag.Usage()
-		os.Exit(-1)
+// A command of the tool, e.g. "validate" or "crowdin update".
+type command struct {
+	// The words of the command, separated with a space.
+	name string
+	// The action performed by the command (see `supportedActionNames`).
+	action  string
+	summary string
+	// The names of the flags accepted by the command, besides -audit-log.
+	flags []string
+	// The other names of the command, e.g. "coverage" for "stats".
+	aliases []string
+}
+
+// The commands of the tool, in the order of the usage.
+var commands = []command{
+	{"validate", actionNameValidate, "Validates the translations against the base strings.", []string{"resdir", "project", "baselocale", "filename", "missing", "fix", "delete-orphans", "suggest", "fill", "review-file", "deadline", "fail-on", "max-warnings", "max-errors", "fail-on-missing", "brands", "from", "issues-conf", "format", "output", "display-language", "config", "locales", "exclude-locales", "keys", "profile", "force", "overlays", "qualified-dirs", "watch", "triage", "since", "changed-only", "summary", "baseline", "glossary"}, nil},
+	{"fix", actionNameFix, "Fixes the problems with a mechanical fix (e.g. the iOS format specifiers) in the strings files, without validating them.", []string{"resdir", "project", "baselocale", "filename", "delete-orphans", "force"}, nil},
+	{"pull", actionNamePull, "Downloads the translations of the pipelines from the providers.", []string{"pipeline-conf", "pipeline-only", "profile", "force"}, nil},
+	{"push", actionNamePush, "Uploads the base strings of the pipelines to the providers.", []string{"pipeline-conf", "pipeline-only", "profile", "force"}, nil},
+	{"crowdin update", actionNameCrowdinUpdate, "Downloads the translations from Crowdin (superseded by 'pull').", []string{"resdir", "filename", "crowdin-conf", "force"}, nil},
+	{"crowdin export", actionNameCrowdinExport, "Builds the Crowdin translations export (superseded by 'pull').", []string{"resdir", "crowdin-conf", "force"}, nil},
+	{"stats", actionNameCoverage, "Prints the translation coverage of the locales.", []string{"resdir", "baselocale", "filename", "format", "display-language", "config", "locales", "exclude-locales", "profile"}, []string{actionNameCoverage}},
+	{"diff", actionNameDiff, "Compares the resources of two locales.", []string{"resdir", "baselocale", "filename", "locale", "against", "format"}, nil},
+	{"unused", actionNameUnused, "Lists (and deletes) the strings not referenced in the sources.", []string{"resdir", "baselocale", "filename", "srcdir", "dynamic-keys", "delete", "force"}, nil},
+	{"format", actionNameFormat, "Pretty-prints the strings files in the canonical format.", []string{"resdir", "baselocale", "filename", "force"}, nil},
+	{"sort", actionNameSort, "Sorts the resources in the strings files.", []string{"resdir", "baselocale", "filename", "sort-by", "force"}, nil},
+	{"merge", actionNameMerge, "Merges the strings files of each values directory into one file.", []string{"resdir", "baselocale", "filename", "merge-into", "force"}, nil},
+	{"feature-isolation", actionNameFeatures, "Validates that the feature modules do not use the strings of each other.", []string{"resdir", "baselocale", "filename", "basemodule", "featuremodules", "fail-on", "max-warnings", "max-errors", "fail-on-missing", "format", "output", "display-language", "summary"}, nil},
+	{"shrink-report", actionNameShrinkReport, "Reports the strings likely to be stripped by the resource shrinker.", []string{"resdir", "baselocale", "filename", "keep-rules", "dynamic-keys", "srcdir", "fail-on", "max-warnings", "max-errors", "fail-on-missing", "format", "output", "display-language", "summary"}, nil},
+	{"apk-import", actionNameApkImport, "Imports the missing translations from an APK.", []string{"resdir", "baselocale", "filename", "apk", "force"}, nil},
+	{"brand-expand", actionNameBrandExpand, "Writes the res directories of the brands with the brand variables expanded.", []string{"resdir", "filename", "brands", "brand", "outdir"}, nil},
+	{"release-notes", actionNameReleaseNotes, "Lists the string changes between two revisions for the translators.", []string{"resdir", "baselocale", "filename", "from", "to", "format", "output", "display-language"}, nil},
+	{"history", actionNameHistory, "Shows the changes of a string.", []string{"resdir", "baselocale", "filename", "key"}, nil},
+	{"serve", actionNameServe, "Serves the translation dashboard.", []string{"resdir", "baselocale", "filename", "listen", "missing", "config", "locales", "exclude-locales", "profile", "qualified-dirs", "pipeline-conf", "pipeline-only", "force"}, nil},
+	{"onboard-locale", actionNameOnboard, "Adds a new locale to the project and the providers.", []string{"resdir", "baselocale", "filename", "locale", "config", "locales", "exclude-locales", "profile", "pipeline-conf", "pipeline-only", "force"}, nil},
+	{"pseudo", actionNamePseudo, "Generates the pseudo-locales.", []string{"resdir", "baselocale", "filename", "locale", "outdir"}, nil},
+}
+
+// Returns the name of the executable, used in the usage.
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
+
+// Returns the command performing the `action`, or nil if there is none.
+func commandOfAction(action string) *command {
+	for i := range commands {
+		if commands[i].action == action {
+			return &commands[i]
+		}
 	}
+	return nil
+}
 
-	var errorList []error = validator.Validate(projectResDirArg, baseLocaleArg, stringsFileNameArg, showMissingArg)
-	errorCount := 0
+// Returns the command whose name (or alias) the `args` start with, and the rest of the `args`.
+func findCommand(args []string) (*command, []string) {
+	for i := range commands {
+		c := &commands[i]
+		for _, name := range append([]string{c.name}, c.aliases...) {
+			words := strings.Fields(name)
+			if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == name {
+				return c, args[len(words):]
+			}
+		}
+	}
+	return nil, args
+}
 
-	if len(errorList) > 0 {
-		for _, e := range errorList {
-			errorCount += 1
-			fmt.Printf("[%d] %s\n", errorCount, e.Error())
+// Parses the command and its flags from the `args` (without the program name), e.g. "validate -resdir res".
+// The "help" command prints the usage of the tool, or of the command following it.
+func parseCommand(args []string) {
+	if args[0] == "help" {
+		if len(args) == 1 {
+			printUsage()
+			os.Exit(0)
+		}
+		c, rest := findCommand(args[1:])
+		if c == nil || len(rest) > 0 {
+			fmt.Printf("Unknown command '%s'.\n", strings.Join(args[1:], " "))
+			os.Exit(exitCodeUsage)
 		}
+		c.flagSet(flag.ContinueOnError).Usage()
+		os.Exit(0)
+	}
+	c, rest := findCommand(args)
+	if c == nil {
+		fmt.Printf("Unknown command '%s'.\n", args[0])
+		printUsage()
+		os.Exit(exitCodeUsage)
+	}
+	flagSet := c.flagSet(flag.ExitOnError)
+	flag.Usage = flagSet.Usage
+	flagSet.Parse(rest)
+	if flagSet.NArg() > 0 {
+		fmt.Printf("Unexpected argument '%s'.\n", flagSet.Arg(0))
+		flagSet.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	actionNameArg = c.action
+}
+
+// Returns the flags of the command, which set the same variables as the flags of the -action form.
+func (c *command) flagSet(errorHandling flag.ErrorHandling) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(fmt.Sprintf("%s %s", programName(), c.name), errorHandling)
+	for _, name := range append(c.flags, "audit-log") {
+		f := flag.CommandLine.Lookup(name)
+		flagSet.Var(f.Value, f.Name, f.Usage)
 	}
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage: %s %s [flags]\n\n%s\n\nFlags:\n", programName(), c.name, c.summary)
+		flagSet.PrintDefaults()
+	}
+	return flagSet
+}
 
-	if errorCount > 0 {
-		fmt.Printf("Found %d errors.\n", errorCount)
-	} else {
-		fmt.Println("No errors found.")
+// Prints the commands of the tool, and the flags of the deprecated -action form.
+func printUsage() {
+	output := flag.CommandLine.Output()
+	fmt.Fprintf(output, "Usage: %s <command> [flags]\n\nCommands:\n", programName())
+	for _, c := range commands {
+		fmt.Fprintf(output, "  %-18s %s\n", c.name, c.summary)
 	}
-	os.Exit(errorCount)
+	fmt.Fprintf(output, "\nRun '%s help <command>' for the flags of a command.\n\n", programName())
+	fmt.Fprintf(output, "The deprecated form '%s -action <action> [flags]' accepts the flags:\n", programName())
+	flag.PrintDefaults()
 }
 
-func crowdinUpdate() {
-	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+func fixFiles() {
+	if !((len(projectResDirArg) > 0 || len(projectDirArg) > 0) && len(stringsFileNameArg) > 0) {
 		flag.Usage()
-		os.Exit(-1)
+		os.Exit(exitCodeUsage)
 	}
-	config, err := loadCrowdinConf()
+	if len(projectDirArg) == 0 {
+		fixStrings(projectResDirArg)
+		if deleteOrphansArg {
+			deleteOrphans(projectResDirArg, nil)
+		}
+		exit(0)
+	}
+	modules, err := project.Discover(projectDirArg)
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(-1)
+		os.Exit(exitCodeError)
 	}
-	if err := crowdin.UpdateStrings(config, projectResDirArg, stringsFileNameArg); err != nil {
+	for _, m := range modules {
+		for _, resDir := range m.ResDirs {
+			if project.IsTestSourceSet(resDir) || !hasStringsFiles(resDir) {
+				continue
+			}
+			fixStrings(resDir)
+			if deleteOrphansArg {
+				deleteOrphans(resDir, m.LowerSourceSets(resDir))
+			}
+		}
+	}
+	exit(0)
+}
+
+func validateStrings() {
+	if !((len(projectResDirArg) > 0 || len(projectDirArg) > 0) && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	if !(formatArg == "" || formatArg == "json" || formatArg == "html" || formatArg == "sarif" || formatArg == "checkstyle") || (watchArg && formatArg != "") || (triageArg && (formatArg != "" || watchArg || len(baselineFileArg) == 0)) || (fillArg && !(suggestArg && showMissingArg)) || (len(reviewFileArg) > 0 && !fillArg) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+
+	config, err := loadValidatorConf()
+	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(-1)
+		os.Exit(exitCodeError)
+	}
+	var brandVariables brands.Brands
+	if len(brandsFileArg) > 0 {
+		if brandVariables, err = brands.Load(brandsFileArg); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+	}
+	var terms glossary.Glossary
+	if len(glossaryFileArg) > 0 {
+		if terms, err = glossary.Load(glossaryFileArg); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+	}
+	overlays := make(map[string]string)
+	if len(overlaysArg) > 0 {
+		for _, entry := range strings.Split(overlaysArg, ",") {
+			parts := strings.SplitN(entry, "=", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+				fmt.Printf("Invalid overlay '%s'; expected 'form-factor=res-dir'.\n", entry)
+				os.Exit(exitCodeUsage)
+			}
+			overlays[parts[0]] = parts[1]
+		}
+	}
+	options := validator.Options{ShowMissing: showMissingArg || failOnMissingArg, Deadline: deadlineArg, Config: config, Brands: brandVariables, Glossary: terms, Suggest: suggestArg, Overlays: overlays, Qualifiers: qualifiedDirsArg}
+	if len(keysArg) > 0 {
+		options.Keys = splitList(keysArg)
+		for _, pattern := range options.Keys {
+			if _, err := path.Match(pattern, ""); err != nil {
+				fmt.Printf("Invalid key pattern '%s'.\n", pattern)
+				os.Exit(exitCodeUsage)
+			}
+		}
+	}
+	if watchArg {
+		watchStrings(options, config)
+	}
+	if triageArg {
+		triageFindings(options, config)
+	}
+	modules := validateModules(options, config)
+	if fillArg {
+		fillSuggestions(modules)
+	}
+	if len(issuesConfigFileArg) > 0 {
+		fileIssues(moduleFindings(modules, config))
+	}
+	reportModuleErrors(modules, config)
+}
+
+// Validates the -project or the -resdir, without the findings listed in the baseline file.
+func validateModules(options validator.Options, config *validator.Config) []moduleErrors {
+	var modules []moduleErrors
+	if len(projectDirArg) > 0 {
+		modules = validateProject(options)
 	} else {
-		fmt.Println("Strings have been updated.")
-		os.Exit(0)
+		if fixArg {
+			fixStrings(projectResDirArg)
+		}
+		if deleteOrphansArg {
+			deleteOrphans(projectResDirArg, nil)
+		}
+		if changedOnlyArg {
+			options.Changes = changesSince(projectResDirArg)
+		}
+		if len(sinceArg) > 0 {
+			options.Since = loadRevision(projectResDirArg)
+		}
+		modules = []moduleErrors{{"", projectResDirArg, validator.Validate(projectResDirArg, baseLocaleArg, stringsFileNameArg, options)}}
+	}
+	if len(baselineFileArg) > 0 {
+		modules = withoutBaselined(modules, config)
 	}
+	return modules
 }
 
-func crowdinExport() {
-	config, err := loadCrowdinConf()
+// Validates the strings and runs an interactive review of the findings not in the baseline yet.
+func triageFindings(options validator.Options, config *validator.Config) {
+	// an empty baseline, so that all the findings are reviewed rather than added to it
+	if _, err := os.Stat(baselineFileArg); os.IsNotExist(err) {
+		if err := report.WriteBaseline(baselineFileArg, []report.Finding{}); err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+	}
+	var items []triage.Item
+	for _, m := range validateModules(options, config) {
+		for _, f := range report.WithModule(report.Findings(m.errors, config), m.module) {
+			items = append(items, triage.Item{Finding: f, ResDir: m.resDir})
+		}
+	}
+	session := triage.NewSession(items, baseLocaleArg, stringsFileNameArg, baselineFileArg, displayLanguageArg)
+	if err := session.Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	exit(0)
+}
+
+// The time to wait after a change of a file for the other changes (e.g. an editor saving a file in a few steps),
+// before validating the strings again.
+const watchDelay = 300 * time.Millisecond
+
+// Validates the strings whenever a file in the res directories changes, and prints the findings that appeared
+// and the ones that were resolved since the previous validation. Exits when the process is interrupted.
+func watchStrings(options validator.Options, config *validator.Config) {
+	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(-1)
+		exit(exitCodeError)
 	}
-	if resp, err := crowdin.ExportStrings(config); err != nil {
+	resDirs := []string{projectResDirArg}
+	if len(projectDirArg) > 0 {
+		modules, err := project.Discover(projectDirArg)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		resDirs = nil
+		for _, m := range modules {
+			resDirs = append(resDirs, m.ResDirs...)
+		}
+	}
+	for _, resDir := range resDirs {
+		if err := watchResDir(watcher, resDir); err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+
+	// the messages of the findings of the previous validation, keyed by the module and the error without the position,
+	// which changes when the lines above are edited
+	previous := make(map[string]string)
+	for i := 0; ; i++ {
+		current := make(map[string]string)
+		counts := make(map[string]int)
+		for _, m := range validateModules(options, config) {
+			for _, e := range m.errors {
+				severity := config.SeverityOf(e)
+				counts[severity] += 1
+				message := describeError(e)
+				if severity != validator.SeverityError {
+					message = fmt.Sprintf("%s: %s", severity, message)
+				}
+				if len(m.module) > 0 {
+					message = fmt.Sprintf("%s: %s", m.module, message)
+				}
+				current[m.module+"\x00"+e.Error()] = message
+			}
+		}
+		for _, key := range sortedKeys(current) {
+			if _, ok := previous[key]; ok {
+				continue
+			}
+			if i == 0 {
+				fmt.Println(current[key])
+			} else {
+				fmt.Printf("+ %s\n", current[key])
+			}
+		}
+		for _, key := range sortedKeys(previous) {
+			if _, ok := current[key]; !ok {
+				fmt.Printf("- %s\n", previous[key])
+			}
+		}
+		previous = current
+		fmt.Printf("[%s] Found %d errors, %d warnings and %d infos. Watching for changes...\n", time.Now().Format("15:04:05"), counts[validator.SeverityError], counts[validator.SeverityWarning], counts[validator.SeverityInfo])
+		waitForChange(watcher, interrupted)
+	}
+}
+
+// Returns the keys of the map `m` in the sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Adds the `resDir` and its values directories to the `watcher`.
+func watchResDir(watcher *fsnotify.Watcher, resDir string) error {
+	if err := watcher.Add(resDir); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(resDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "values") {
+			if err := watcher.Add(filepath.Join(resDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Waits until an XML file watched by the `watcher` changes, and no other change follows it for the `watchDelay`.
+// The values directories created in the meantime are watched too. Exits when the process is `interrupted`.
+func waitForChange(watcher *fsnotify.Watcher, interrupted chan os.Signal) {
+	// receives when the changes settle; nil until a file changes
+	var settled <-chan time.Time
+	for {
+		select {
+		case event := <-watcher.Events:
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create && strings.HasPrefix(filepath.Base(event.Name), "values") {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watcher.Add(event.Name); err != nil {
+						fmt.Println(err.Error())
+					}
+					settled = time.After(watchDelay)
+				}
+			}
+			if filepath.Ext(event.Name) == ".xml" {
+				settled = time.After(watchDelay)
+			}
+		case err := <-watcher.Errors:
+			fmt.Println(err.Error())
+		case <-settled:
+			return
+		case <-interrupted:
+			watcher.Close()
+			exit(0)
+		}
+	}
+}
+
+// The validation errors of a module of a multi-module project.
+type moduleErrors struct {
+	// The name of the module, e.g. ":app" or ":app (debug)" for a source set other than "main";
+	// empty when a single res directory is validated.
+	module string
+	// The res directory of the module's source set.
+	resDir string
+	errors []error
+}
+
+// Validates the res directories of the modules of the project at `projectDirArg` which have the strings files.
+// The res directories of the source sets other than "main" are merged over the source sets they override.
+func validateProject(options validator.Options) []moduleErrors {
+	modules, err := project.Discover(projectDirArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	var results []moduleErrors
+	for _, m := range modules {
+		for _, resDir := range m.ResDirs {
+			if project.IsTestSourceSet(resDir) || !hasStringsFiles(resDir) {
+				continue
+			}
+			if fixArg {
+				fixStrings(resDir)
+			}
+			if deleteOrphansArg {
+				deleteOrphans(resDir, m.LowerSourceSets(resDir))
+			}
+			name := m.Name
+			if sourceSet := project.SourceSet(resDir); sourceSet != "main" {
+				name += fmt.Sprintf(" (%s)", sourceSet)
+			}
+			sourceSetOptions := options
+			sourceSetOptions.SourceSets = m.LowerSourceSets(resDir)
+			if changedOnlyArg {
+				sourceSetOptions.Changes = changesSince(resDir)
+			}
+			if len(sinceArg) > 0 {
+				sourceSetOptions.Since = loadRevision(resDir)
+			}
+			results = append(results, moduleErrors{name, resDir, validator.Validate(resDir, baseLocaleArg, stringsFileNameArg, sourceSetOptions)})
+		}
+	}
+	if len(results) == 0 {
+		fmt.Printf("No modules with the %s files found in %s.\n", stringsFileNameArg, projectDirArg)
+		exit(exitCodeError)
+	}
+	return results
+}
+
+// Returns the errors of the `modules` without the findings listed in the baseline file.
+// If the baseline file does not exist, it is created with the findings and no errors are returned.
+func withoutBaselined(modules []moduleErrors, config *validator.Config) []moduleErrors {
+	if _, err := os.Stat(baselineFileArg); os.IsNotExist(err) {
+		findings := moduleFindings(modules, config)
+		if err := report.WriteBaseline(baselineFileArg, findings); err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		if len(formatArg) == 0 {
+			fmt.Printf("Created the baseline %s with %d finding(s).\n", baselineFileArg, len(findings))
+		}
+	}
+	baseline, err := report.LoadBaseline(baselineFileArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	var filtered []moduleErrors
+	for _, m := range modules {
+		filtered = append(filtered, moduleErrors{m.module, m.resDir, baseline.Filter(m.errors, m.module, config)})
+	}
+	return filtered
+}
+
+// Returns the findings of all the `modules`.
+func moduleFindings(modules []moduleErrors, config *validator.Config) []report.Finding {
+	var findings []report.Finding
+	for _, m := range modules {
+		findings = append(findings, report.WithModule(report.Findings(m.errors, config), m.module)...)
+	}
+	return findings
+}
+
+// Returns the changes of the strings files in the `resDir` since the -from revision (HEAD if empty).
+func changesSince(resDir string) *validator.Changes {
+	ref := fromRefArg
+	if len(ref) == 0 {
+		ref = "HEAD"
+	}
+	changes, err := validator.ChangesSince(resDir, baseLocaleArg, stringsFileNameArg, ref)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	return changes
+}
+
+// Returns the base strings of the `resDir` in the -since revision.
+func loadRevision(resDir string) *validator.Revision {
+	revision, err := validator.LoadRevision(resDir, baseLocaleArg, stringsFileNameArg, sinceArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	return revision
+}
+
+// Returns true if the `resDir` has any strings files of the base or the other locales.
+func hasStringsFiles(resDir string) bool {
+	paths, err := resources.OtherLocalePaths(resDir, baseLocaleArg, stringsFileNameArg)
+	if err == nil && len(paths) > 0 {
+		return true
+	}
+	basePaths, err := resources.ExpandPath(filepath.Join(resDir, resources.ValuesDir(baseLocaleArg), stringsFileNameArg))
+	if err != nil || len(basePaths) == 0 {
+		return false
+	}
+	info, err := os.Stat(basePaths[0])
+	return err == nil && !info.IsDir()
+}
+
+func validateFeatureIsolation() {
+	if !(len(baseModuleDirArg) > 0 && len(featureModuleDirsArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+
+	featureModuleDirs := strings.Split(featureModuleDirsArg, ",")
+	var errorList []error = validator.ValidateFeatureIsolation(baseModuleDirArg, featureModuleDirs, baseLocaleArg, stringsFileNameArg)
+	reportErrors(errorList, nil)
+}
+
+func shrinkReport() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+
+	var dynamicKeys []string
+	if len(dynamicKeysFileArg) > 0 {
+		lines, err := readLines(dynamicKeysFileArg)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+		dynamicKeys = lines
+	}
+	var errorList []error = validator.ValidateShrinkSafety(projectResDirArg, baseLocaleArg, stringsFileNameArg, keepRulesFileArg, dynamicKeys, srcDirArg)
+	reportErrors(errorList, nil)
+}
+
+func apkImport() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(apkFileArg) > 0) {
+		flag.Usage()
+		exit(exitCodeUsage)
+	}
+	lockProject(projectResDirArg)
+	if count, err := apk.ImportMissingTranslations(apkFileArg, projectResDirArg, baseLocaleArg, stringsFileNameArg); err != nil {
 		fmt.Println(err.Error())
-		os.Exit(-1)
+		exit(exitCodeError)
 	} else {
-		fmt.Println(resp)
-		os.Exit(0)
+		fmt.Printf("Imported %d translations.\n", count)
+		exit(0)
 	}
 }
 
-func loadCrowdinConf() (*crowdin.CrowdinConfig, error) {
-	if len(crowdinConfigFileArg) == 0 {
-		return nil, errors.New("The path to Crowdin configuration file is required.")
+func brandExpand() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(brandsFileArg) > 0 && len(outDirArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
 	}
-	file, err := os.Open(crowdinConfigFileArg)
+	brandVariables, err := brands.Load(brandsFileArg)
 	if err != nil {
-		return nil, err
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
 	}
-	defer file.Close()
-	var config crowdin.CrowdinConfig
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&config); err != nil {
-		return nil, err
+	brandNames := brandVariables.Names()
+	if len(brandArg) > 0 {
+		brandNames = []string{brandArg}
 	}
-	return &config, nil
+	for _, brand := range brandNames {
+		if err := brands.Expand(brandVariables, brand, projectResDirArg, stringsFileNameArg, filepath.Join(outDirArg, brand, "res")); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+	}
+	fmt.Printf("Expanded %d brand(s).\n", len(brandNames))
+	os.Exit(0)
+}
+
+// Lists the base strings not referenced in the sources (Java, Kotlin and XML files) of the -srcdir,
+// and deletes them from the strings files of all locales with -delete.
+func unusedStrings() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	srcDir := srcDirArg
+	if len(srcDir) == 0 {
+		srcDir = filepath.Dir(filepath.Clean(projectResDirArg))
+	}
+	var dynamicKeys []string
+	if len(dynamicKeysFileArg) > 0 {
+		lines, err := readLines(dynamicKeysFileArg)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+		dynamicKeys = lines
+	}
+	basePath := filepath.Join(projectResDirArg, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+	baseResources, err := resources.ParseFile(basePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	var names []string
+	for _, el := range baseResources.Strings {
+		names = append(names, el.Name)
+	}
+	unused, err := usage.FindUnused(srcDir, names, dynamicKeys)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if len(unused) == 0 {
+		fmt.Println("No unused strings found.")
+		os.Exit(0)
+	}
+	for _, name := range unused {
+		fmt.Println(name)
+	}
+	if !deleteArg {
+		fmt.Printf("Found %d unused strings.\n", len(unused))
+		os.Exit(0)
+	}
+	lockProject(projectResDirArg)
+	paths, err := resources.OtherLocalePaths(projectResDirArg, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	unusedNames := make(map[string]bool)
+	for _, name := range unused {
+		unusedNames[name] = true
+	}
+	deletedCount := 0
+	for _, path := range append([]string{basePath}, paths...) {
+		files, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for _, file := range files {
+			count, err := resources.RemoveResources(file, unusedNames, actionNameUnused)
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			deletedCount += count
+		}
+	}
+	fmt.Printf("Deleted %d unused strings (%d elements in all locales).\n", len(unused), deletedCount)
+	exit(0)
+}
+
+func formatStrings() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	lockProject(projectResDirArg)
+	paths, err := resources.OtherLocalePaths(projectResDirArg, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	basePath := filepath.Join(projectResDirArg, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+	formattedCount := 0
+	fileCount := 0
+	for _, path := range append([]string{basePath}, paths...) {
+		files, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for _, file := range files {
+			fileCount += 1
+			changed, err := resources.FormatFile(file, actionNameFormat)
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			if changed {
+				formattedCount += 1
+				fmt.Println(file)
+			}
+		}
+	}
+	fmt.Printf("Formatted %d of %d files.\n", formattedCount, fileCount)
+	exit(0)
+}
+
+func sortStrings() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) || !(sortByArg == "base" || sortByArg == "name") {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	lockProject(projectResDirArg)
+	paths, err := resources.OtherLocalePaths(projectResDirArg, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	basePath := filepath.Join(projectResDirArg, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+	baseFiles, err := resources.ExpandPath(basePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	less := resources.LessName
+	if sortByArg == "base" {
+		var names []string
+		for _, file := range baseFiles {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			declarations, err := resources.ParseDeclarations(data)
+			if err != nil {
+				fmt.Println(fmt.Sprintf("%s: %s", file, err.Error()))
+				exit(exitCodeError)
+			}
+			for _, d := range declarations {
+				names = append(names, d.Name)
+			}
+		}
+		less = resources.ByNames(names)
+	} else {
+		paths = append([]string{basePath}, paths...)
+	}
+	sortedCount := 0
+	for _, path := range paths {
+		files, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for _, file := range files {
+			changed, err := resources.SortFile(file, less, actionNameSort)
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			if changed {
+				sortedCount += 1
+				fmt.Println(file)
+			}
+		}
+	}
+	fmt.Printf("Sorted %d files.\n", sortedCount)
+	exit(0)
+}
+
+func mergeStrings() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(mergeIntoArg) > 0) || resources.IsFileSet(mergeIntoArg) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	lockProject(projectResDirArg)
+	paths, err := resources.OtherLocalePaths(projectResDirArg, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	basePath := filepath.Join(projectResDirArg, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+	mergedCount := 0
+	conflictCount := 0
+	for _, path := range append([]string{basePath}, paths...) {
+		files, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		target := filepath.Join(filepath.Dir(path), mergeIntoArg)
+		if len(files) == 0 || (len(files) == 1 && files[0] == target) {
+			continue
+		}
+		duplicateCount, conflicts, err := resources.MergeFiles(target, files, actionNameMerge)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for _, c := range conflicts {
+			fmt.Printf("%s: The <%s> at %s:%d differs from the one at %s:%d, which is kept.\n", c.Name, c.Element, c.Path, c.Line, c.KeptPath, c.KeptLine)
+		}
+		fmt.Printf("Merged %d files into %s (%d duplicates dropped).\n", len(files), target, duplicateCount)
+		mergedCount += 1
+		conflictCount += len(conflicts)
+	}
+	if mergedCount == 0 {
+		fmt.Println("No files to merge.")
+	}
+	if conflictCount > 0 {
+		fmt.Printf("Found %d conflicts; review the dropped declarations.\n", conflictCount)
+		exit(exitCodeFailure)
+	}
+	exit(0)
+}
+
+func diffLocales() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(localeArg) > 0) || !(formatArg == "" || formatArg == "json") {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	against := againstArg
+	if len(against) == 0 {
+		against = baseLocaleArg
+	}
+	if against == localeArg {
+		fmt.Printf("The locale '%s' cannot be compared against itself.\n", localeArg)
+		os.Exit(exitCodeUsage)
+	}
+	result, err := diff.Compare(projectResDirArg, stringsFileNameArg, localeArg, against)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if formatArg == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+		os.Exit(0)
+	}
+	fmt.Printf("Comparing %s against %s:\n", resources.ValuesDir(localeArg), resources.ValuesDir(against))
+	for _, d := range result.Differences {
+		switch d.Kind {
+		case diff.Missing:
+			fmt.Printf("- %s: '%s'\n", d.Key, d.AgainstText)
+		case diff.Extra:
+			fmt.Printf("+ %s: '%s'\n", d.Key, d.Text)
+		case diff.Changed:
+			fmt.Printf("~ %s: '%s' -> '%s'\n", d.Key, d.AgainstText, d.Text)
+		}
+	}
+	fmt.Printf("%d missing, %d extra, %d changed.\n", result.Count(diff.Missing), result.Count(diff.Extra), result.Count(diff.Changed))
+	os.Exit(0)
+}
+
+func printCoverage() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) || !(formatArg == "" || formatArg == "json") {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	config, err := loadValidatorConf()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	coverages, err := coverage.Compute(projectResDirArg, baseLocaleArg, stringsFileNameArg, config)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if formatArg == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(coverages); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+		os.Exit(0)
+	}
+	describe := func(c coverage.Counts) string {
+		return fmt.Sprintf("%d/%d", c.Translated, c.Total)
+	}
+	fmt.Printf("%-12s %10s %10s %10s %8s %9s\n", "Locale", "Strings", "Plurals", "Arrays", "Missing", "Complete")
+	for _, c := range coverages {
+		fmt.Printf("%-12s %10s %10s %10s %8d %8.1f%%", c.Locale, describe(c.Strings), describe(c.Plurals), describe(c.Arrays), c.All.Missing, c.Percent)
+		if name := locales.DisplayName(c.Locale, displayLanguageArg); len(name) > 0 {
+			fmt.Printf("  %s", name)
+		}
+		fmt.Println()
+	}
+	os.Exit(0)
+}
+
+func pseudoLocales() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	locales := []string{pseudo.LocaleAccented, pseudo.LocaleBidi}
+	if len(localeArg) > 0 {
+		if !resources.IsPseudoLocale(localeArg) {
+			fmt.Printf("Locale '%s' is not a pseudo-locale, use %s or %s.\n", localeArg, pseudo.LocaleAccented, pseudo.LocaleBidi)
+			os.Exit(exitCodeUsage)
+		}
+		locales = []string{localeArg}
+	}
+	outDir := outDirArg
+	if len(outDir) == 0 {
+		outDir = projectResDirArg
+	}
+	for _, locale := range locales {
+		if _, err := pseudo.Generate(projectResDirArg, baseLocaleArg, stringsFileNameArg, locale, outDir); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+	}
+	fmt.Printf("Generated %d pseudo-locale(s).\n", len(locales))
+	os.Exit(0)
+}
+
+func releaseNotes() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(fromRefArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	if len(formatArg) == 0 {
+		formatArg = "markdown"
+	}
+	if formatArg != "markdown" && !(formatArg == "xlsx" && len(outputFileArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	notes, err := releasenotes.Generate(projectResDirArg, baseLocaleArg, stringsFileNameArg, fromRefArg, toRefArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if len(displayLanguageArg) > 0 {
+		for i := range notes {
+			notes[i].DisplayName = locales.DisplayName(notes[i].Locale, displayLanguageArg)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if len(outputFileArg) > 0 {
+		file, err := os.Create(outputFileArg)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitCodeError)
+		}
+		defer file.Close()
+		out = file
+	}
+	if formatArg == "xlsx" {
+		err = releasenotes.WriteXLSX(out, notes)
+	} else {
+		toRef := toRefArg
+		if len(toRef) == 0 {
+			toRef = "working tree"
+		}
+		err = releasenotes.WriteMarkdown(out, fmt.Sprintf("String changes from %s to %s", fromRefArg, toRef), notes)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+}
+
+// Opens and closes the issues for the persistent findings.
+func fileIssues(findings []report.Finding) {
+	issuesConfig, err := issues.LoadConfig(issuesConfigFileArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	opened, closed, err := issues.Sync(issuesConfig, findings)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	if len(formatArg) == 0 {
+		fmt.Printf("Opened %d and closed %d issue(s).\n", opened, closed)
+	}
+}
+
+// Deletes the orphaned translations (the strings, plurals and string arrays without a base value) from the strings files
+// of the other locales of the `resDir`. A resource is kept if it is declared in the base strings file of the `resDir`
+// or of any of the `lowerResDirs` (the source sets the `resDir` is merged over, e.g. "main" for a flavor).
+func deleteOrphans(resDir string, lowerResDirs []string) {
+	lockProject(resDir)
+	declared := make(map[string]bool)
+	for _, dir := range append([]string{resDir}, lowerResDirs...) {
+		basePath := filepath.Join(dir, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+		baseResources, err := resources.ParseFile(basePath)
+		if dir != resDir && os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for name := range baseResources.Names() {
+			declared[name] = true
+		}
+	}
+	paths, err := resources.OtherLocalePaths(resDir, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	deletedCount := 0
+	for _, path := range paths {
+		files, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		for _, file := range files {
+			res, err := resources.ParseFile(file)
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			orphans := make(map[string]bool)
+			for name := range res.Names() {
+				if !declared[name] {
+					orphans[name] = true
+				}
+			}
+			if len(orphans) == 0 {
+				continue
+			}
+			count, err := resources.RemoveResources(file, orphans, "delete-orphans")
+			if err != nil {
+				fmt.Println(err.Error())
+				exit(exitCodeError)
+			}
+			deletedCount += count
+		}
+	}
+	if deletedCount > 0 && len(formatArg) == 0 {
+		fmt.Printf("Deleted %d translations without a base value.\n", deletedCount)
+	}
+}
+
+// Fixes the iOS format specifiers in the base and locale strings files of the `resDir`.
+func fixStrings(resDir string) {
+	lockProject(resDir)
+	paths, err := resources.OtherLocalePaths(resDir, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	basePath := filepath.Join(resDir, resources.ValuesDir(baseLocaleArg), stringsFileNameArg)
+	baseResources, err := resources.ParseFile(basePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	unformatted := make(map[string]bool)
+	for _, el := range baseResources.Strings {
+		if !el.IsFormatted() {
+			unformatted[el.Name] = true
+		}
+	}
+	paths = append(paths, basePath)
+	var files []string
+	for _, path := range paths {
+		expanded, err := resources.ExpandPath(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		files = append(files, expanded...)
+	}
+	fixedCount := 0
+	normalizedCount := 0
+	for _, path := range files {
+		count, err := validator.FixIOSSpecifiers(path, unformatted)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		fixedCount += count
+		normalized, err := validator.FixNormalization(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		if normalized {
+			normalizedCount += 1
+		}
+	}
+	fmt.Printf("Fixed %d format specifier(s).\n", fixedCount)
+	if normalizedCount > 0 {
+		fmt.Printf("Normalized %d file(s) to the Unicode NFC form.\n", normalizedCount)
+	}
+}
+
+func keyHistory() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(keyArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	histories, err := history.KeyHistory(projectResDirArg, baseLocaleArg, stringsFileNameArg, keyArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if err := history.WriteText(os.Stdout, keyArg, histories); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+}
+
+// Reports the errors of a single res directory (see `reportModuleErrors`).
+func reportErrors(errorList []error, config *validator.Config) {
+	reportModuleErrors([]moduleErrors{{"", projectResDirArg, errorList}}, config)
+}
+
+// Prints the errors of the `modules` with their severities (according to the `config`), and exits
+// with `exitCodeFailure` if any error is at least as severe as the -fail-on severity (errors are
+// accepted up to -max-errors), if there are more warnings than -max-warnings, or if a translation
+// is missing with -fail-on-missing; otherwise exits with zero.
+// If the validation did not finish before the deadline, the exit code is `exitCodeDeadlineExceeded`,
+// and if any file could not be read or parsed, it is `exitCodeError`.
+func reportModuleErrors(modules []moduleErrors, config *validator.Config) {
+	counts := make(map[string]int)
+	findingCount := 0
+	failed := false
+	// true if a file could not be read or parsed
+	broken := false
+	suggestionCounts := make(map[bool]int)
+	missingCount := 0
+	var deadlineError *validator.DeadlineExceededError
+
+	for _, m := range modules {
+		if len(m.module) > 0 && len(formatArg) == 0 && len(m.errors) > 0 {
+			fmt.Printf("Module %s:\n", m.module)
+		}
+		for _, e := range m.errors {
+			if de, ok := e.(*validator.DeadlineExceededError); ok {
+				deadlineError = de
+				continue
+			}
+			switch e.(type) {
+			case *validator.ValidationError, *validator.ResourceMissingError, *validator.DanglingReferenceError:
+			default:
+				broken = true
+			}
+			findingCount += 1
+			severity := config.SeverityOf(e)
+			counts[severity] += 1
+			if _, ok := e.(*validator.ResourceMissingError); ok {
+				missingCount += 1
+			}
+			if validator.IsAtLeast(severity, failOnArg) && (severity != validator.SeverityError || maxErrorsArg < 0) {
+				failed = true
+			}
+			if len(formatArg) > 0 {
+				continue
+			}
+			if me, ok := e.(*validator.ResourceMissingError); ok && me.Suggestion != nil {
+				suggestionCounts[me.Suggestion.NeedsReview] += 1
+			}
+			message := describeError(e)
+			if severity == validator.SeverityError {
+				fmt.Printf("[%d] %s\n", findingCount, message)
+			} else {
+				fmt.Printf("[%d] %s: %s\n", findingCount, severity, message)
+			}
+		}
+	}
+	if maxWarningsArg >= 0 && counts[validator.SeverityWarning] > maxWarningsArg {
+		failed = true
+	}
+	if maxErrorsArg >= 0 && counts[validator.SeverityError] > maxErrorsArg {
+		failed = true
+	}
+	if failOnMissingArg && missingCount > 0 {
+		failed = true
+	}
+
+	if len(formatArg) > 0 {
+		resDirs := make(map[string]string)
+		for _, m := range modules {
+			resDirs[m.module] = m.resDir
+		}
+		if err := writeReport(moduleFindings(modules, config), resDirs); err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+	} else {
+		if findingCount > 0 {
+			fmt.Printf("Found %d errors, %d warnings and %d infos.\n", counts[validator.SeverityError], counts[validator.SeverityWarning], counts[validator.SeverityInfo])
+		} else {
+			fmt.Println("No errors found.")
+		}
+		if len(suggestionCounts) > 0 {
+			fmt.Printf("Suggested %d translations: %d approved and %d needing review.\n", suggestionCounts[false]+suggestionCounts[true], suggestionCounts[false], suggestionCounts[true])
+		}
+		if maxWarningsArg >= 0 && counts[validator.SeverityWarning] > maxWarningsArg {
+			fmt.Printf("The number of warnings exceeds the maximum of %d.\n", maxWarningsArg)
+		}
+		if maxErrorsArg >= 0 && counts[validator.SeverityError] > maxErrorsArg {
+			fmt.Printf("The number of errors exceeds the maximum of %d.\n", maxErrorsArg)
+		}
+		if failOnMissingArg && missingCount > 0 {
+			fmt.Printf("%d translations are missing.\n", missingCount)
+		}
+		if deadlineError != nil {
+			fmt.Println(deadlineError.Error())
+		}
+	}
+	code := 0
+	if broken {
+		code = exitCodeError
+	} else if deadlineError != nil {
+		code = exitCodeDeadlineExceeded
+	} else if failed {
+		code = exitCodeFailure
+	}
+	if summaryArg {
+		fmt.Fprintf(os.Stderr, "Summary: %d finding(s): %d error(s), %d warning(s), %d info(s); exit code %d\n", findingCount, counts[validator.SeverityError], counts[validator.SeverityWarning], counts[validator.SeverityInfo], code)
+	}
+	exit(code)
+}
+
+// Returns the message of the validation error `e` printed in the plain text output,
+// with the locale names, the suggested fix or translation, and the position of the resource.
+func describeError(e error) string {
+	message := e.Error()
+	switch ve := e.(type) {
+	case *validator.ValidationError:
+		message = strings.Replace(message, ve.Path, locales.DescribePath(ve.Path, displayLanguageArg), 1)
+		if len(ve.Fix) > 0 {
+			message += fmt.Sprintf(" (fix: '%s')", strings.Replace(ve.Fix, "\n", "\\n", -1))
+		}
+	case *validator.DanglingReferenceError:
+		message = strings.Replace(message, ve.Path, locales.DescribePath(ve.Path, displayLanguageArg), 1)
+	case *validator.ResourceMissingError:
+		message = strings.Replace(message, ve.Path, locales.DescribePath(ve.Path, displayLanguageArg), 1)
+		if s := ve.Suggestion; s != nil {
+			message += fmt.Sprintf(" (suggestion: '%s' from %s, %.0f%% match, %s)", s.Translation, s.Key, s.Score*100, describeConfidence(s))
+		}
+	}
+	if p := validator.PositionOf(e); p != nil {
+		message += fmt.Sprintf(" (at %s:%d:%d)", p.File, p.Line, p.Column)
+	}
+	return message
+}
+
+// Describes the confidence of the suggestion and its breakdown, e.g. "80% confidence, needs review: placeholders differ".
+func describeConfidence(s *validator.Suggestion) string {
+	description := fmt.Sprintf("%.0f%% confidence", s.Confidence*100)
+	if !s.NeedsReview {
+		return description + ", approved"
+	}
+	var problems []string
+	for _, reason := range s.ReviewReasons() {
+		if reason == validator.ReviewReasonLength {
+			reason = fmt.Sprintf("length ratio %.1f", s.LengthRatio)
+		}
+		problems = append(problems, reason)
+	}
+	return description + ", needs review: " + strings.Join(problems, ", ")
+}
+
+// Writes the approved suggested translations of the validated `modules` to their strings files,
+// and the ones needing a review to the -review-file, printing the breakdown of the suggestions.
+// The breakdown is printed to the standard error with a -format, so that it does not mix with the report.
+func fillSuggestions(modules []moduleErrors) {
+	output := os.Stdout
+	if len(formatArg) > 0 {
+		output = os.Stderr
+	}
+	var review []validator.ReviewEntry
+	reasons := make(map[string]int)
+	written, skipped := 0, 0
+	for _, m := range modules {
+		lockProject(m.resDir)
+		fillReport, err := validator.FillSuggestions(m.resDir, m.errors)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		var paths []string
+		for path := range fillReport.Written {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Fprintf(output, "Filled %d translation(s) in %s.\n", fillReport.Written[path], filepath.Join(m.resDir, path))
+		}
+		for _, entry := range fillReport.NeedsReview {
+			entry.Path = filepath.Join(m.resDir, entry.Path)
+			review = append(review, entry)
+		}
+		for reason, count := range fillReport.Reasons {
+			reasons[reason] += count
+		}
+		written += fillReport.WrittenCount()
+		skipped += fillReport.Skipped
+	}
+	fmt.Fprintf(output, "Filled %d approved translations; %d need review", written, len(review))
+	if len(reasons) > 0 {
+		var breakdown []string
+		for _, reason := range []string{validator.ReviewReasonPlaceholders, validator.ReviewReasonLength, validator.ReviewReasonSimilarity} {
+			if count := reasons[reason]; count > 0 {
+				breakdown = append(breakdown, fmt.Sprintf("%s: %d", reason, count))
+			}
+		}
+		fmt.Fprintf(output, " (%s)", strings.Join(breakdown, ", "))
+	}
+	fmt.Fprintln(output, ".")
+	if skipped > 0 {
+		fmt.Fprintf(output, "Skipped %d approved translations, since their strings files do not exist.\n", skipped)
+	}
+	if len(reviewFileArg) > 0 {
+		if err := validator.WriteReviewFile(reviewFileArg, review); err != nil {
+			fmt.Println(err.Error())
+			exit(exitCodeError)
+		}
+		fmt.Fprintf(output, "Wrote the translations needing review to %s.\n", reviewFileArg)
+	}
+}
+
+func pull() {
+	pipelines := selectPipelines()
+	for _, p := range pipelines {
+		lockProject(p.ResDir)
+		count, err := pipeline.Pull(p)
+		if err != nil {
+			printError(err)
+			exit(exitCodeError)
+		}
+		fmt.Printf("Pipeline '%s': updated %d locale(s).\n", p.Name, count)
+	}
+	exit(0)
+}
+
+func push() {
+	pipelines := selectPipelines()
+	for _, p := range pipelines {
+		lockProject(p.ResDir)
+		if err := pipeline.Push(p); err != nil {
+			printError(err)
+			exit(exitCodeError)
+		}
+		fmt.Printf("Pipeline '%s': uploaded the base strings.\n", p.Name)
+	}
+	exit(0)
+}
+
+// Serves the dashboard with the coverage and the findings of the -resdir project.
+// The translations can be pulled from the dashboard if -pipeline-conf is given.
+func serve() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	config, err := loadValidatorConf()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	s := &server.Server{
+		ResDir:     projectResDirArg,
+		BaseLocale: baseLocaleArg,
+		Filename:   stringsFileNameArg,
+		Options:    validator.Options{ShowMissing: showMissingArg, Config: config, Qualifiers: qualifiedDirsArg},
+		Addr:       listenArg,
+	}
+	if len(pipelineConfigFileArg) > 0 {
+		pipelines := selectPipelines()
+		s.Pull = func() error {
+			for _, p := range pipelines {
+				l, err := lock.Acquire(lock.PathFor(p.ResDir), actionNameArg, forceArg)
+				if err != nil {
+					return err
+				}
+				_, err = pipeline.Pull(p)
+				l.Release()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	s.Validate()
+	fmt.Printf("Serving the dashboard at http://%s/\n", listenArg)
+	if err := http.ListenAndServe(listenArg, s.Handler()); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+}
+
+// Adds a new locale to the project: creates its strings file seeded with the strings that are not translated
+// (see `onboard.Seed`), adds the language to the providers of the -pipeline-conf pipelines (if given),
+// and prints the resConfigs of the build.gradle file and the number of words to translate.
+func onboardLocale() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0 && len(localeArg) > 0) {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	locale := localeArg
+	if !resources.IsLocaleQualifier(locale) {
+		locale = resources.LocaleFromLanguageTag(locale)
+	}
+	config, err := loadValidatorConf()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	var locked []string
+	if config != nil {
+		locked = config.IdenticalAllowed
+	}
+	var pipelines []*pipeline.Pipeline
+	if len(pipelineConfigFileArg) > 0 {
+		pipelines = selectPipelines()
+	}
+
+	lockProject(projectResDirArg)
+	result, err := onboard.Seed(projectResDirArg, baseLocaleArg, stringsFileNameArg, locale, locked)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	fmt.Printf("Created %s with %d seeded string(s).\n", result.Path, len(result.Seeded))
+	for _, p := range pipelines {
+		if err := pipeline.AddLanguage(p, locale); err != nil {
+			printError(err)
+			exit(exitCodeError)
+		}
+		fmt.Printf("Pipeline '%s': added the language.\n", p.Name)
+	}
+	configs, err := onboard.ResConfigs(projectResDirArg, baseLocaleArg, stringsFileNameArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	fmt.Printf("If the app limits its locales, update the build.gradle file:\n    resConfigs \"%s\"\n", strings.Join(configs, "\", \""))
+	fmt.Printf("%d word(s) to translate.\n", result.WordCount)
+	exit(0)
+}
+
+// Prints the error, followed by the remediation hint of the provider errors (see `apierr.Hinter`).
+func printError(err error) {
+	fmt.Println(err.Error())
+	if h, ok := err.(apierr.Hinter); ok && len(h.RemediationHint()) > 0 {
+		fmt.Printf("Hint: %s\n", h.RemediationHint())
+	}
+}
+
+// Loads the pipelines configuration and returns the pipelines selected with the -pipeline-only flag.
+func selectPipelines() []*pipeline.Pipeline {
+	if len(pipelineConfigFileArg) == 0 {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+	config, err := pipeline.LoadConfig(pipelineConfigFileArg, profileArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	pipelines, err := config.Select(pipelineOnlyArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(exitCodeError)
+	}
+	if len(config.Budgets) > 0 {
+		budget.Enable(config.BudgetState, config.Budgets)
+	}
+	return pipelines
+}
+
+// Writes the findings in the -format to the -output file (or the standard output).
+// The files of the findings are in the res directories of their modules, in the `resDirs`.
+func writeReport(findings []report.Finding, resDirs map[string]string) error {
+	var out io.Writer = os.Stdout
+	if len(outputFileArg) > 0 {
+		file, err := os.Create(outputFileArg)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	if formatArg == "html" {
+		return report.WriteHTML(out, findings)
+	}
+	resDirOf := func(module string) string {
+		return resDirs[module]
+	}
+	if formatArg == "sarif" {
+		return report.WriteSARIF(out, findings, resDirOf)
+	}
+	if formatArg == "checkstyle" {
+		return report.WriteCheckstyle(out, findings, resDirOf)
+	}
+	return report.WriteJSON(out, findings)
+}
+
+func crowdinUpdate() {
+	if !(len(projectResDirArg) > 0 && len(stringsFileNameArg) > 0) {
+		flag.Usage()
+		exit(exitCodeUsage)
+	}
+	config, err := loadCrowdinConf()
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	lockProject(projectResDirArg)
+	if err := crowdin.UpdateStrings(config, projectResDirArg, stringsFileNameArg); err != nil {
+		printError(err)
+		exit(exitCodeError)
+	} else {
+		fmt.Println("Strings have been updated.")
+		exit(0)
+	}
+}
+
+func crowdinExport() {
+	config, err := loadCrowdinConf()
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	lockProject(projectResDirArg)
+	if resp, err := crowdin.ExportStrings(config); err != nil {
+		printError(err)
+		exit(exitCodeError)
+	} else {
+		fmt.Println(resp)
+		exit(0)
+	}
+}
+
+// Acquires the lock of the project containing the `resDir`, so that the actions modifying the resources
+// or talking to the providers do not run concurrently. Exits if the project is locked by another process.
+// Does nothing if the `resDir` is empty (e.g. -resdir is not given to an action not requiring it),
+// rather than locking the working directory.
+func lockProject(resDir string) {
+	if len(resDir) == 0 {
+		return
+	}
+	path := lock.PathFor(resDir)
+	if _, ok := projectLocks[path]; ok {
+		return
+	}
+	l, err := lock.Acquire(path, actionNameArg, forceArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		exit(exitCodeError)
+	}
+	projectLocks[path] = l
+}
+
+// Releases the project locks and exits with the `code`.
+func exit(code int) {
+	for _, l := range projectLocks {
+		l.Release()
+	}
+	os.Exit(code)
+}
+
+// Loads the validator configuration, if the configuration file was specified,
+// with the locales overridden by -locales and -exclude-locales.
+func loadValidatorConf() (*validator.Config, error) {
+	var config *validator.Config
+	if len(configFileArg) == 0 {
+		if len(profileArg) > 0 {
+			return nil, errors.New("The path to the configuration file is required when using a profile.")
+		}
+	} else {
+		var err error
+		if config, err = validator.LoadConfig(configFileArg, profileArg); err != nil {
+			return nil, err
+		}
+	}
+	if len(localesArg) == 0 && len(excludeLocalesArg) == 0 {
+		return config, nil
+	}
+	if config == nil {
+		config = &validator.Config{}
+	}
+	if len(localesArg) > 0 {
+		config.Locales = splitList(localesArg)
+	}
+	if len(excludeLocalesArg) > 0 {
+		config.ExcludeLocales = splitList(excludeLocalesArg)
+	}
+	return config, nil
+}
+
+// Splits the comma-separated `list`, skipping the empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Loads the Crowdin configuration from the -crowdin-conf file, with the API key and the project name overridden
+// by the environment variables. The file is optional if both are set in the environment.
+func loadCrowdinConf() (*crowdin.CrowdinConfig, error) {
+	var config crowdin.CrowdinConfig
+	if len(crowdinConfigFileArg) > 0 {
+		file, err := os.Open(crowdinConfigFileArg)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		decoder := json.NewDecoder(file)
+		if err := decoder.Decode(&config); err != nil {
+			return nil, err
+		}
+	}
+	config.ApplyEnv("")
+	if len(config.Key) == 0 || len(config.ProjectName) == 0 {
+		return nil, errors.New(fmt.Sprintf("The path to Crowdin configuration file, or the %s and %s environment variables, are required.", crowdin.EnvKey, crowdin.EnvProjectName))
+	}
+	return &config, nil
+}
+
+// Reads the non-empty lines of the file at `path`, skipping lines starting with '#'.
+func readLines(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
 }
 
 // Returns true if the `actionName` is supported by this tool.
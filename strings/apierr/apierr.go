@@ -0,0 +1,118 @@
+package apierr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// The maximal length of the response body kept in the error details.
+const maxBodyLength = 500
+
+// The details of a failed request to the API of a provider.
+type Details struct {
+	// The name of the provider, e.g. "crowdin".
+	Provider string
+	// What the request did, e.g. "downloading the translations".
+	Operation  string
+	StatusCode int
+	Status     string
+	// The beginning of the response body, which usually explains the failure.
+	Body string
+	// Describes how to fix the failure, e.g. "Check that the API key has access to the project".
+	Hint string
+}
+
+func (d *Details) describe(problem string) string {
+	message := fmt.Sprintf("%s: %s failed: %s (%s)", d.Provider, d.Operation, problem, d.Status)
+	if len(d.Body) > 0 {
+		message += ": " + d.Body
+	}
+	return message
+}
+
+// Reported when the credentials are missing, invalid or lack the permissions (HTTP 401 and 403).
+type AuthError struct{ Details }
+
+// Reported when the API quota or the rate limit is exhausted (HTTP 429).
+type QuotaError struct{ Details }
+
+// Reported when the project, the file or the endpoint does not exist (HTTP 404).
+type NotFoundError struct{ Details }
+
+// Reported when the provider rejects the request content, e.g. an invalid strings file (HTTP 400, 409, 413 and 422).
+type ValidationRejectedError struct{ Details }
+
+// Reported for the other failed responses, e.g. server errors.
+type ResponseError struct{ Details }
+
+func (e *AuthError) Error() string {
+	return e.describe("not authorized")
+}
+
+func (e *QuotaError) Error() string {
+	return e.describe("quota exceeded")
+}
+
+func (e *NotFoundError) Error() string {
+	return e.describe("not found")
+}
+
+func (e *ValidationRejectedError) Error() string {
+	return e.describe("rejected")
+}
+
+func (e *ResponseError) Error() string {
+	return e.describe("unexpected response")
+}
+
+// Implemented by the errors that describe how to fix the failure.
+type Hinter interface {
+	RemediationHint() string
+}
+
+func (d *Details) RemediationHint() string {
+	return d.Hint
+}
+
+// The remediation hints used when the provider does not give a more specific one.
+var defaultHints = map[int]string{
+	http.StatusUnauthorized:          "Check that the API key is set and valid.",
+	http.StatusForbidden:             "Check that the API key has access to the project (e.g. the token lacks the project scope).",
+	http.StatusTooManyRequests:       "Retry later, or lower the API budget in the pipeline configuration.",
+	http.StatusNotFound:              "Check the project and file names in the configuration.",
+	http.StatusBadRequest:            "Validate the uploaded file with the 'validate' action.",
+	http.StatusConflict:              "Retry when the concurrent operation on the project finishes.",
+	http.StatusRequestEntityTooLarge: "Split the strings file; it is larger than the provider accepts.",
+	http.StatusUnprocessableEntity:   "Validate the uploaded file with the 'validate' action.",
+}
+
+// Returns nil if the `resp` succeeded, otherwise an error typed by the status code,
+// with the beginning of the response body and the `hint` (or a default hint, if empty).
+// The response body is read but not closed.
+func FromResponse(provider, operation string, resp *http.Response, hint string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	text := strings.TrimSpace(string(body))
+	if len(text) > maxBodyLength {
+		text = text[:maxBodyLength] + "..."
+	}
+	if len(hint) == 0 {
+		hint = defaultHints[resp.StatusCode]
+	}
+	details := Details{provider, operation, resp.StatusCode, resp.Status, text, hint}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthError{details}
+	case http.StatusTooManyRequests:
+		return &QuotaError{details}
+	case http.StatusNotFound:
+		return &NotFoundError{details}
+	case http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return &ValidationRejectedError{details}
+	}
+	return &ResponseError{details}
+}
@@ -0,0 +1,277 @@
+package apk
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"unicode/utf16"
+)
+
+// Chunk types of the compiled resource table ("resources.arsc").
+const (
+	chunkStringPool = 0x0001
+	chunkTable      = 0x0002
+	chunkPackage    = 0x0200
+	chunkType       = 0x0201
+)
+
+const (
+	stringPoolUtf8Flag = 1 << 8
+	entryComplexFlag   = 0x0001
+	entryCompactFlag   = 0x0008
+	typeSparseFlag     = 0x01
+	valueTypeString    = 0x03
+	noEntry            = 0xFFFFFFFF
+)
+
+// The string resources of an APK, keyed by the locale (e.g. "de" or "pt-rBR"; empty for the default locale)
+// and then by the string name.
+type Strings map[string]map[string]string
+
+// Reads the `<string>` resources from the compiled resource table of the APK at `path`.
+// Plurals and string arrays are not supported.
+func ReadStrings(path string) (Strings, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	for _, f := range reader.File {
+		if f.Name != "resources.arsc" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		return parseResourceTable(data)
+	}
+	return nil, errors.New(fmt.Sprintf("%s does not contain resources.arsc", path))
+}
+
+type chunkHeader struct {
+	chunkType  uint16
+	headerSize int
+	size       int
+}
+
+func readChunkHeader(data []byte, offset int) (chunkHeader, error) {
+	if offset+8 > len(data) {
+		return chunkHeader{}, errors.New("Unexpected end of the resource table")
+	}
+	header := chunkHeader{
+		binary.LittleEndian.Uint16(data[offset:]),
+		int(binary.LittleEndian.Uint16(data[offset+2:])),
+		int(binary.LittleEndian.Uint32(data[offset+4:])),
+	}
+	if header.size < 8 || offset+header.size > len(data) {
+		return chunkHeader{}, errors.New("Malformed chunk in the resource table")
+	}
+	return header, nil
+}
+
+func parseResourceTable(data []byte) (Strings, error) {
+	table, err := readChunkHeader(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if table.chunkType != chunkTable {
+		return nil, errors.New("Not a resource table")
+	}
+
+	result := make(Strings)
+	var globalStrings []string
+	for offset := table.headerSize; offset < table.size; {
+		chunk, err := readChunkHeader(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		switch chunk.chunkType {
+		case chunkStringPool:
+			if globalStrings, err = parseStringPool(data[offset : offset+chunk.size]); err != nil {
+				return nil, err
+			}
+		case chunkPackage:
+			if err := parsePackage(data[offset:offset+chunk.size], globalStrings, result); err != nil {
+				return nil, err
+			}
+		}
+		offset += chunk.size
+	}
+	return result, nil
+}
+
+func parseStringPool(data []byte) ([]string, error) {
+	header, err := readChunkHeader(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	stringCount := int(binary.LittleEndian.Uint32(data[8:]))
+	flags := binary.LittleEndian.Uint32(data[16:])
+	stringsStart := int(binary.LittleEndian.Uint32(data[20:]))
+
+	pool := make([]string, stringCount)
+	for i := 0; i < stringCount; i++ {
+		offset := stringsStart + int(binary.LittleEndian.Uint32(data[header.headerSize+i*4:]))
+		if offset >= len(data) {
+			return nil, errors.New("Malformed string pool in the resource table")
+		}
+		if flags&stringPoolUtf8Flag != 0 {
+			pool[i] = readUtf8String(data[offset:])
+		} else {
+			pool[i] = readUtf16String(data[offset:])
+		}
+	}
+	return pool, nil
+}
+
+// Reads a length stored in one or two bytes.
+func readUtf8Length(data []byte) (length, size int) {
+	if data[0]&0x80 != 0 {
+		return int(data[0]&0x7f)<<8 | int(data[1]), 2
+	}
+	return int(data[0]), 1
+}
+
+func readUtf8String(data []byte) string {
+	_, charsSize := readUtf8Length(data)
+	length, bytesSize := readUtf8Length(data[charsSize:])
+	start := charsSize + bytesSize
+	return string(data[start : start+length])
+}
+
+func readUtf16String(data []byte) string {
+	length := int(binary.LittleEndian.Uint16(data))
+	start := 2
+	if length&0x8000 != 0 {
+		length = (length&0x7fff)<<16 | int(binary.LittleEndian.Uint16(data[2:]))
+		start = 4
+	}
+	chars := make([]uint16, length)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(data[start+i*2:])
+	}
+	return string(utf16.Decode(chars))
+}
+
+func parsePackage(data []byte, globalStrings []string, result Strings) error {
+	header, err := readChunkHeader(data, 0)
+	if err != nil {
+		return err
+	}
+	// The package header: id (4 bytes), name (256 bytes), typeStrings, lastPublicType, keyStrings, lastPublicKey.
+	typeStringsOffset := int(binary.LittleEndian.Uint32(data[268:]))
+	keyStringsOffset := int(binary.LittleEndian.Uint32(data[276:]))
+	typeNames, err := parseStringPool(data[typeStringsOffset:])
+	if err != nil {
+		return err
+	}
+	keyNames, err := parseStringPool(data[keyStringsOffset:])
+	if err != nil {
+		return err
+	}
+
+	for offset := header.headerSize; offset < header.size; {
+		chunk, err := readChunkHeader(data, offset)
+		if err != nil {
+			return err
+		}
+		if chunk.chunkType == chunkType {
+			typeId := int(data[offset+8])
+			if typeId > 0 && typeId <= len(typeNames) && typeNames[typeId-1] == "string" {
+				if err := parseStringType(data[offset:offset+chunk.size], globalStrings, keyNames, result); err != nil {
+					return err
+				}
+			}
+		}
+		offset += chunk.size
+	}
+	return nil
+}
+
+func parseStringType(data []byte, globalStrings, keyNames []string, result Strings) error {
+	header, err := readChunkHeader(data, 0)
+	if err != nil {
+		return err
+	}
+	flags := data[9]
+	entryCount := int(binary.LittleEndian.Uint32(data[12:]))
+	entriesStart := int(binary.LittleEndian.Uint32(data[16:]))
+	// The configuration starts at 20: size (4 bytes), mcc (2), mnc (2), language (2), country (2).
+	locale := decodeLocale(data[28:30], data[30:32])
+
+	localeStrings, ok := result[locale]
+	if !ok {
+		localeStrings = make(map[string]string)
+		result[locale] = localeStrings
+	}
+
+	var entryOffsets []int
+	for i := 0; i < entryCount; i++ {
+		if flags&typeSparseFlag != 0 {
+			entryOffsets = append(entryOffsets, int(binary.LittleEndian.Uint16(data[header.headerSize+i*4+2:]))*4)
+			continue
+		}
+		entryOffset := binary.LittleEndian.Uint32(data[header.headerSize+i*4:])
+		if entryOffset != noEntry {
+			entryOffsets = append(entryOffsets, int(entryOffset))
+		}
+	}
+
+	for _, entryOffset := range entryOffsets {
+		entry := data[entriesStart+entryOffset:]
+		entryFlags := binary.LittleEndian.Uint16(entry[2:])
+		if entryFlags&entryComplexFlag != 0 {
+			continue
+		}
+		var keyIndex, valueData int
+		var dataType byte
+		if entryFlags&entryCompactFlag != 0 {
+			// A compact entry: the key index in place of the size, the data type in the high byte of the flags.
+			keyIndex = int(binary.LittleEndian.Uint16(entry))
+			dataType = byte(entryFlags >> 8)
+			valueData = int(binary.LittleEndian.Uint32(entry[4:]))
+		} else {
+			entrySize := int(binary.LittleEndian.Uint16(entry))
+			keyIndex = int(binary.LittleEndian.Uint32(entry[4:]))
+			value := entry[entrySize:]
+			dataType = value[3]
+			valueData = int(binary.LittleEndian.Uint32(value[4:]))
+		}
+		if dataType == valueTypeString && keyIndex < len(keyNames) && valueData < len(globalStrings) {
+			localeStrings[keyNames[keyIndex]] = globalStrings[valueData]
+		}
+	}
+	return nil
+}
+
+// Decodes the language and country of a resource configuration into a locale qualifier like "pt-rBR".
+func decodeLocale(language, country []byte) string {
+	locale := decodeLocalePart(language, 'a')
+	if region := decodeLocalePart(country, '0'); len(region) > 0 {
+		locale += "-r" + region
+	}
+	return locale
+}
+
+// Decodes a two-letter code, or a packed three-letter code (when the highest bit is set).
+func decodeLocalePart(in []byte, base byte) string {
+	if in[0] == 0 {
+		return ""
+	}
+	if in[0]&0x80 == 0 {
+		return string(in)
+	}
+	first := in[1] & 0x1f
+	second := ((in[1] & 0xe0) >> 5) + ((in[0] & 0x03) << 3)
+	third := (in[0] & 0x7c) >> 2
+	return string([]byte{first + base, second + base, third + base})
+}
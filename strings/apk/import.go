@@ -0,0 +1,87 @@
+package apk
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Imports the translations from the APK at `apkPath` into the project's locale files,
+// for the strings that are missing there.
+// A translation is matched by the string name, or else by the base (default locale) value,
+// so that the strings renamed in the project are matched too.
+// Only the existing locale files are updated. Returns the number of imported translations.
+func ImportMissingTranslations(apkPath, resDir, baseLocale, stringsFilename string) (int, error) {
+	apkStrings, err := ReadStrings(apkPath)
+	if err != nil {
+		return 0, err
+	}
+	baseResources, err := resources.Parse(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return 0, err
+	}
+
+	// Maps the base values to the names of the APK strings with that value.
+	apkNamesByValue := make(map[string][]string)
+	for name, value := range apkStrings[""] {
+		apkNamesByValue[value] = append(apkNamesByValue[value], name)
+	}
+	for _, names := range apkNamesByValue {
+		sort.Strings(names)
+	}
+
+	var locales []string
+	for locale := range apkStrings {
+		if len(locale) > 0 && locale != baseLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+
+	importedCount := 0
+	for _, locale := range locales {
+		path := filepath.Join(resDir, resources.ValuesDir(locale), stringsFilename)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			log.Printf("Skipping %s, since it does not exist in the project\n", path)
+			continue
+		}
+		localeResources, err := resources.ParseFile(path)
+		if err != nil {
+			return importedCount, err
+		}
+
+		var imported []resources.String
+		for _, baseElem := range baseResources.Strings {
+			if !baseElem.IsTranslatable() || localeResources.FindString(baseElem.Name) != nil {
+				continue
+			}
+			if translation, ok := findTranslation(apkStrings[locale], apkNamesByValue, baseElem); ok {
+				imported = append(imported, resources.String{Name: baseElem.Name, RawValue: resources.EscapeValue(translation)})
+			}
+		}
+		if len(imported) == 0 {
+			continue
+		}
+
+		log.Printf("Importing %d translation(s) into %s\n", len(imported), path)
+		if err := resources.AppendStrings(path, imported, "apk:"+filepath.Base(apkPath)); err != nil {
+			return importedCount, err
+		}
+		importedCount += len(imported)
+	}
+	return importedCount, nil
+}
+
+func findTranslation(localeStrings map[string]string, apkNamesByValue map[string][]string, baseElem resources.String) (string, bool) {
+	if translation, ok := localeStrings[baseElem.Name]; ok {
+		return translation, true
+	}
+	for _, name := range apkNamesByValue[resources.UnescapeValue(baseElem.Value)] {
+		if translation, ok := localeStrings[name]; ok {
+			return translation, true
+		}
+	}
+	return "", false
+}
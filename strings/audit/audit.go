@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// The operations recorded in the audit log.
+const (
+	OperationCreate = "create"
+	OperationModify = "modify"
+	OperationDelete = "delete"
+)
+
+// A single line of the audit log.
+type Entry struct {
+	Time time.Time
+	// The action of the tool that made the change, e.g. "pull".
+	Action string
+	// The changed file.
+	Path      string
+	Operation string
+	// Where the content came from, e.g. "crowdin:the-project-name" or "apk:app-release.apk".
+	Source string
+	// The SHA-256 hashes of the file content before and after the change; empty if the file did not exist.
+	OldHash string `json:",omitempty"`
+	NewHash string `json:",omitempty"`
+}
+
+// The path of the audit log; the changes are not recorded if empty.
+var logPath string
+
+// The action of the tool recorded in the entries.
+var currentAction string
+
+// Enables appending the entries to the JSONL file at `path`, recording the `action` as the cause of the changes.
+func Enable(path, action string) {
+	logPath = path
+	currentAction = action
+}
+
+// Writes the `data` to the file at `path` and records the change with its `source`.
+func WriteFile(path string, data []byte, source string) error {
+	operation := OperationCreate
+	oldHash := ""
+	if oldData, err := ioutil.ReadFile(path); err == nil {
+		operation = OperationModify
+		oldHash = hash(oldData)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return record(Entry{time.Now().UTC(), currentAction, path, operation, source, oldHash, hash(data)})
+}
+
+// Removes the file at `path` and records the change with its `source`.
+func Remove(path, source string) error {
+	oldHash := ""
+	if oldData, err := ioutil.ReadFile(path); err == nil {
+		oldHash = hash(oldData)
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return record(Entry{time.Now().UTC(), currentAction, path, OperationDelete, source, oldHash, ""})
+}
+
+func record(entry Entry) error {
+	if len(logPath) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
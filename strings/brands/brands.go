@@ -0,0 +1,126 @@
+package brands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/resources"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The brand variables, read from a JSON file like:
+// {"acme": {"app_name": "Acme", "support_email": "help@acme.com"}, "globex": {"app_name": "Globex", ...}}
+// Maps the brand name to the values of the variables for that brand.
+type Brands map[string]map[string]string
+
+// Matches a brand variable like {app_name}.
+var VariableRegex *regexp.Regexp = regexp.MustCompile("\\{([a-zA-Z_][a-zA-Z0-9_]*)\\}")
+
+// Escapes a variable value for the use inside a `<string>` element.
+var valueEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\\", "\\\\", "'", "\\'", "\"", "\\\"")
+
+// Reads the brand variables from the JSON file at `path`.
+func Load(path string) (Brands, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var brands Brands
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&brands); err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+// Returns true if the `variable` is declared for any brand.
+func (b Brands) IsDeclared(variable string) bool {
+	for _, variables := range b {
+		if _, ok := variables[variable]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the sorted names of the brands.
+func (b Brands) Names() []string {
+	var names []string
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Finds a brand-specific value hardcoded in the `text`.
+// Returns the variable that should be used instead and the brand, or empty strings.
+func (b Brands) FindHardcodedValue(text string) (variable, brand string) {
+	for _, brandName := range b.Names() {
+		variables := b[brandName]
+		var names []string
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value := variables[name]; len(value) > 0 && strings.Contains(text, value) {
+				return name, brandName
+			}
+		}
+	}
+	return "", ""
+}
+
+// Writes the string files from `resDir` with the variables substituted with the values of the `brand`
+// into the `outDir` (keeping the "values*" directory structure).
+func Expand(b Brands, brand, resDir, stringsFilename, outDir string) error {
+	variables, ok := b[brand]
+	if !ok {
+		return errors.New(fmt.Sprintf("The brand '%s' is not declared.", brand))
+	}
+
+	paths, err := resources.OtherLocalePaths(resDir, "", stringsFilename)
+	if err != nil {
+		return err
+	}
+	paths = append(paths, filepath.Join(resDir, resources.ValuesDir(""), stringsFilename))
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var undeclared error
+		expanded := VariableRegex.ReplaceAllStringFunc(string(data), func(match string) string {
+			name := VariableRegex.FindStringSubmatch(match)[1]
+			value, ok := variables[name]
+			if !ok {
+				undeclared = errors.New(fmt.Sprintf("The variable {%s} in %s is not declared for the brand '%s'.", name, path, brand))
+				return match
+			}
+			return valueEscaper.Replace(value)
+		})
+		if undeclared != nil {
+			return undeclared
+		}
+
+		targetPath := filepath.Join(outDir, resources.ShortPath(resDir, path))
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
+		log.Printf("Writing %s\n", targetPath)
+		if err := audit.WriteFile(targetPath, []byte(expanded), "brand:"+brand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/lock"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The limits of the requests to the API of a provider; zero means no limit.
+type Limit struct {
+	PerMinute int
+	PerDay    int
+}
+
+// The token buckets of a provider, shared by all the processes using the same state file.
+type bucket struct {
+	Minute  float64
+	Day     float64
+	Updated time.Time
+}
+
+// How long to wait for the lock of the state file, held by another process.
+const lockTimeout = 10 * time.Second
+
+// The path of the state file used when the configuration does not specify it.
+var DefaultStatePath = filepath.Join(os.TempDir(), "android-tools-api-budget.json")
+
+var (
+	// The limits keyed by the provider (e.g. "crowdin"); the requests are not limited if nil.
+	limits    map[string]*Limit
+	statePath string
+	// Queues the requests of the current process.
+	mutex sync.Mutex
+)
+
+// Enables limiting the requests to the providers, with the state of the budgets stored at `path`
+// (the `DefaultStatePath` if empty), so the limits apply to all the processes using the same state file.
+func Enable(path string, providerLimits map[string]*Limit) {
+	if len(path) == 0 {
+		path = DefaultStatePath
+	}
+	statePath = path
+	limits = providerLimits
+}
+
+// Takes a request from the budget of the `provider`, waiting until the budget allows it.
+func Take(provider string) error {
+	limit := limits[provider]
+	if limit == nil || (limit.PerMinute <= 0 && limit.PerDay <= 0) {
+		return nil
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	for {
+		wait, err := tryTake(provider, limit)
+		if err != nil {
+			return err
+		}
+		if wait == 0 {
+			return nil
+		}
+		log.Printf("The API budget of %s is exhausted; waiting %s before the next request.", provider, wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+}
+
+// Takes a request from the budget of the `provider` if it is available,
+// or returns how long to wait until it will be.
+func tryTake(provider string, limit *Limit) (time.Duration, error) {
+	l, err := acquireLock()
+	if err != nil {
+		return 0, err
+	}
+	defer l.Release()
+
+	buckets := make(map[string]*bucket)
+	if data, err := ioutil.ReadFile(statePath); err == nil {
+		if err := json.Unmarshal(data, &buckets); err != nil {
+			return 0, errors.New(fmt.Sprintf("The API budget state %s is invalid: %s", statePath, err.Error()))
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	now := time.Now()
+	b := buckets[provider]
+	if b == nil {
+		b = &bucket{float64(limit.PerMinute), float64(limit.PerDay), now}
+		buckets[provider] = b
+	}
+	elapsed := now.Sub(b.Updated).Seconds()
+	b.Minute = refill(b.Minute, limit.PerMinute, time.Minute, elapsed)
+	b.Day = refill(b.Day, limit.PerDay, 24*time.Hour, elapsed)
+	b.Updated = now
+
+	var wait time.Duration
+	if limit.PerMinute > 0 && b.Minute < 1 {
+		wait = waitFor(b.Minute, limit.PerMinute, time.Minute)
+	}
+	if limit.PerDay > 0 && b.Day < 1 {
+		if w := waitFor(b.Day, limit.PerDay, 24*time.Hour); w > wait {
+			wait = w
+		}
+	}
+	if wait == 0 && limit.PerMinute > 0 {
+		b.Minute -= 1
+	}
+	if wait == 0 && limit.PerDay > 0 {
+		b.Day -= 1
+	}
+
+	data, err := json.Marshal(buckets)
+	if err != nil {
+		return 0, err
+	}
+	return wait, ioutil.WriteFile(statePath, data, 0644)
+}
+
+// Returns the `tokens` of a bucket with the `capacity` per `period`, after `elapsed` seconds.
+func refill(tokens float64, capacity int, period time.Duration, elapsed float64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return math.Min(float64(capacity), tokens+elapsed*float64(capacity)/period.Seconds())
+}
+
+// Returns how long it takes a bucket with the `capacity` per `period` to refill from `tokens` to one token.
+func waitFor(tokens float64, capacity int, period time.Duration) time.Duration {
+	seconds := (1 - tokens) * period.Seconds() / float64(capacity)
+	return time.Duration(math.Ceil(seconds*1000)) * time.Millisecond
+}
+
+// Acquires the lock of the state file, waiting while it is held by another process.
+func acquireLock() (*lock.Lock, error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		l, err := lock.Acquire(statePath+".lock", "api-budget", false)
+		if err == nil || time.Now().After(deadline) {
+			return l, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+package coverage
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"sort"
+)
+
+// The numbers of the translatable base resources of a kind, and of the translated and missing ones.
+type Counts struct {
+	Total      int
+	Translated int
+	Missing    int
+}
+
+func (c *Counts) add(translated bool) {
+	c.Total += 1
+	if translated {
+		c.Translated += 1
+	} else {
+		c.Missing += 1
+	}
+}
+
+// The translation coverage of a locale.
+type Locale struct {
+	Locale  string
+	Strings Counts
+	Plurals Counts
+	Arrays  Counts
+	// The sums of the strings, plurals and arrays.
+	All Counts
+	// The percentage (0-100) of the translated resources.
+	Percent float64
+}
+
+// Returns the translation coverage of the locales in the `resDir` (the ones included in the `config`, which may be nil),
+// sorted by the locale. The directories with qualifiers other than the locale (e.g. "values-night") are skipped.
+func Compute(resDir, baseLocale, stringsFilename string, config *validator.Config) ([]Locale, error) {
+	base, err := resources.Parse(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	var locales []Locale
+	for _, path := range paths {
+		locale := resources.LocaleFromPath(path)
+		if !resources.IsLocaleQualifier(locale) || resources.IsPseudoLocale(locale) || !config.IsLocaleIncluded(resources.ShortPath(resDir, path)) {
+			continue
+		}
+		res, err := resources.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		coverage := Locale{Locale: locale}
+		for _, el := range base.Strings {
+			if el.IsTranslatable() {
+				coverage.Strings.add(res.FindString(el.Name) != nil)
+			}
+		}
+		for _, el := range base.Plurals {
+			if el.IsTranslatable() {
+				coverage.Plurals.add(res.FindPlural(el.Name) != nil)
+			}
+		}
+		for _, el := range base.StringArrays {
+			if el.IsTranslatable() {
+				coverage.Arrays.add(res.FindStringArray(el.Name) != nil)
+			}
+		}
+		for _, c := range []Counts{coverage.Strings, coverage.Plurals, coverage.Arrays} {
+			coverage.All.Total += c.Total
+			coverage.All.Translated += c.Translated
+			coverage.All.Missing += c.Missing
+		}
+		coverage.Percent = 100
+		if coverage.All.Total > 0 {
+			coverage.Percent = float64(coverage.All.Translated) * 100 / float64(coverage.All.Total)
+		}
+		locales = append(locales, coverage)
+	}
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i].Locale < locales[j].Locale
+	})
+	return locales, nil
+}
@@ -2,33 +2,90 @@ package crowdin
 
 import (
 	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"github.com/daaku/go.httpzip"
+	"github.com/armatys/android-tools/strings/apierr"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/budget"
+	"github.com/armatys/android-tools/strings/resources"
 	"io"
+	"io/ioutil"
 	"log"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 )
 
 type CrowdinConfig struct {
-	Key          string
+	// The API key of the project; overridden by the CROWDIN_API_KEY environment variable (see `ApplyEnv`).
+	Key string
+	// Overridden by the CROWDIN_PROJECT environment variable.
 	ProjectName  string
 	FileName     string
 	LocaleToCopy []string
 }
 
-var validLocaleRegexp *regexp.Regexp = regexp.MustCompile("^[a-z]{2}(\\-[A-Z]{2})?/")
-var hyphenRegexp *regexp.Regexp = regexp.MustCompile("-")
+// The environment variables overriding the configuration, so that the API key does not have to be stored in a file.
+const (
+	EnvKey         = "CROWDIN_API_KEY"
+	EnvProjectName = "CROWDIN_PROJECT"
+)
+
+// Overrides the API key and the project name with the environment variables `EnvKey` and `EnvProjectName`,
+// or with the ones with the `suffix` (e.g. "CROWDIN_API_KEY_STAGING" for the suffix "STAGING"), which take precedence.
+// The empty variables are ignored.
+func (c *CrowdinConfig) ApplyEnv(suffix string) {
+	for _, name := range []string{"", suffix} {
+		if len(name) > 0 {
+			name = "_" + name
+		}
+		if key := os.Getenv(EnvKey + name); len(key) > 0 {
+			c.Key = key
+		}
+		if projectName := os.Getenv(EnvProjectName + name); len(projectName) > 0 {
+			c.ProjectName = projectName
+		}
+	}
+}
+
+// The name of the provider, under which the requests are counted in the API budget.
+const Provider = "crowdin"
+
+var validLocaleRegexp *regexp.Regexp = regexp.MustCompile("^[a-z]{2,3}(\\-[a-zA-Z0-9]{2,8})*/")
+
+// Returns the pattern of the paths of the strings files named `fileName` (without the ".xml" extension)
+// in the zip file with the translations, e.g. "pt-BR/strings.xml" or "es-419/strings.xml".
+func compileStringsFileRegex(fileName string) (*regexp.Regexp, error) {
+	return regexp.Compile(fmt.Sprintf("^([a-zA-Z0-9\\-]+)/%s\\.xml", fileName))
+}
+
+// Returns the Crowdin locale (e.g. "es-419") of the zip file entry `name` if it is a strings file
+// matching the `stringsFileRegex` (see `compileStringsFileRegex`) in a valid locale directory.
+func zipEntryLocale(stringsFileRegex *regexp.Regexp, name string) (string, bool) {
+	if match := stringsFileRegex.FindStringSubmatch(name); match != nil && validLocaleRegexp.MatchString(name) {
+		return match[1], true
+	}
+	return "", false
+}
 
 func ExportStrings(config *CrowdinConfig) (string, error) {
-	url := fmt.Sprintf("http://api.crowdin.net/api/project/%s/export?key=%s", config.ProjectName, config.Key)
+	if err := budget.Take(Provider); err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("https://api.crowdin.net/api/project/%s/export?key=%s", config.ProjectName, config.Key)
 	resp, err := http.Get(url)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
+	if err := responseError(config, "exporting the translations", resp); err != nil {
+		return "", err
+	}
 
 	var buf []byte
 	_, err = resp.Body.Read(buf)
@@ -51,25 +108,24 @@ func shouldCopyTranslations(config *CrowdinConfig, localeIdentifier string) bool
 }
 
 func UpdateStrings(config *CrowdinConfig, resDir, stringsFilename string) error {
-	expr := fmt.Sprintf("^([a-zA-Z\\-]+)/%s\\.xml", config.FileName)
-	stringsFileRegex, err := regexp.Compile(expr)
+	stringsFileRegex, err := compileStringsFileRegex(config.FileName)
 	if err != nil {
 		return err
 	}
 
-	log.Println("Downloading zip file")
-	url := fmt.Sprintf("http://api.crowdin.net/api/project/%s/download/all.zip?key=%s", config.ProjectName, config.Key)
-	zipReader, err := httpzip.ReadURL(url)
+	if err := budget.Take(Provider); err != nil {
+		return err
+	}
+	zipReader, err := downloadAll(config)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("Extracting into %s directory...", resDir)
 	for _, f := range zipReader.File {
-		if match := stringsFileRegex.FindStringSubmatch(f.FileHeader.Name); match != nil && validLocaleRegexp.MatchString(f.FileHeader.Name) {
-			localeIdentifier := match[1]
+		if localeIdentifier, ok := zipEntryLocale(stringsFileRegex, f.FileHeader.Name); ok {
 			if shouldCopyTranslations(config, localeIdentifier) {
-				if err := copyStringsToResources(f, localeIdentifier, stringsFilename, resDir); err != nil {
+				if err := copyStringsToResources(f, localeIdentifier, stringsFilename, resDir, config.ProjectName); err != nil {
 					return err
 				}
 			}
@@ -79,8 +135,8 @@ func UpdateStrings(config *CrowdinConfig, resDir, stringsFilename string) error
 	return nil
 }
 
-func copyStringsToResources(f *zip.File, localeIdentifier, stringsFilename, resDir string) error {
-	valuesDirName := fmt.Sprintf("values-%s", hyphenRegexp.ReplaceAllLiteralString(localeIdentifier, "-r"))
+func copyStringsToResources(f *zip.File, localeIdentifier, stringsFilename, resDir, projectName string) error {
+	valuesDirName := resources.ValuesDir(resources.LocaleFromLanguageTag(localeIdentifier))
 	targetValuesDir := path.Join(resDir, valuesDirName)
 	targetStringsFilename := path.Join(targetValuesDir, stringsFilename)
 
@@ -96,15 +152,165 @@ func copyStringsToResources(f *zip.File, localeIdentifier, stringsFilename, resD
 	}
 	defer sourceFile.Close()
 
-	targetFile, err := os.Create(targetStringsFilename)
+	data, err := ioutil.ReadAll(sourceFile)
 	if err != nil {
 		return err
 	}
-	defer targetFile.Close()
-
-	if _, err := io.Copy(targetFile, sourceFile); err != nil {
+	if err := audit.WriteFile(targetStringsFilename, data, "crowdin:"+projectName); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Downloads the translations and returns the content of the strings files keyed by the Crowdin locale (e.g. "pt-BR").
+func DownloadTranslations(config *CrowdinConfig) (map[string][]byte, error) {
+	stringsFileRegex, err := compileStringsFileRegex(config.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Take(Provider); err != nil {
+		return nil, err
+	}
+	zipReader, err := downloadAll(config)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make(map[string][]byte)
+	for _, f := range zipReader.File {
+		if localeIdentifier, ok := zipEntryLocale(stringsFileRegex, f.FileHeader.Name); ok {
+			if !shouldCopyTranslations(config, localeIdentifier) {
+				continue
+			}
+			sourceFile, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadAll(sourceFile)
+			sourceFile.Close()
+			if err != nil {
+				return nil, err
+			}
+			translations[localeIdentifier] = data
+		}
+	}
+	return translations, nil
+}
+
+// Downloads the zip file with the translations of all the locales of the project.
+func downloadAll(config *CrowdinConfig) (*zip.Reader, error) {
+	log.Println("Downloading zip file")
+	url := fmt.Sprintf("https://api.crowdin.net/api/project/%s/download/all.zip?key=%s", config.ProjectName, config.Key)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := responseError(config, "downloading the translations", resp); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// Uploads the base strings file at `path` as the source file of the project.
+func UploadStrings(config *CrowdinConfig, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(fmt.Sprintf("files[%s.xml]", config.FileName), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if err := budget.Take(Provider); err != nil {
+		return err
+	}
+	log.Printf("Uploading %s\n", path)
+	url := fmt.Sprintf("https://api.crowdin.net/api/project/%s/update-file?key=%s", config.ProjectName, config.Key)
+	resp, err := http.Post(url, writer.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return responseError(config, fmt.Sprintf("uploading %s", path), resp)
+}
+
+// The project information returned by the Crowdin API.
+type projectInfo struct {
+	Languages []struct {
+		Code string
+	}
+}
+
+// Adds the `language` (the Crowdin locale, e.g. "pt-BR") to the target languages of the project,
+// unless the project already has it.
+func AddLanguage(config *CrowdinConfig, language string) error {
+	if err := budget.Take(Provider); err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://api.crowdin.net/api/project/%s/info?key=%s&json", config.ProjectName, config.Key)
+	resp, err := http.Post(url, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := responseError(config, "reading the project languages", resp); err != nil {
+		return err
+	}
+	var info projectInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return err
+	}
+
+	form := neturl.Values{}
+	for _, l := range info.Languages {
+		if l.Code == language {
+			log.Printf("The project %s already has the language %s\n", config.ProjectName, language)
+			return nil
+		}
+		form.Add("languages[]", l.Code)
+	}
+	form.Add("languages[]", language)
+
+	if err := budget.Take(Provider); err != nil {
+		return err
+	}
+	log.Printf("Adding the language %s to the project %s\n", language, config.ProjectName)
+	url = fmt.Sprintf("https://api.crowdin.net/api/project/%s/edit-project?key=%s", config.ProjectName, config.Key)
+	editResp, err := http.PostForm(url, form)
+	if err != nil {
+		return err
+	}
+	defer editResp.Body.Close()
+	return responseError(config, fmt.Sprintf("adding the language %s", language), editResp)
+}
+
+// Returns nil if the `resp` succeeded, otherwise the typed error (see `apierr.FromResponse`)
+// with the remediation hint specific to Crowdin.
+func responseError(config *CrowdinConfig, operation string, resp *http.Response) error {
+	hint := ""
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		hint = fmt.Sprintf("Check that the Key in the Crowdin configuration is the API key of the project '%s'.", config.ProjectName)
+	case http.StatusNotFound:
+		hint = fmt.Sprintf("Check that the project '%s' exists and has the file '%s.xml'.", config.ProjectName, config.FileName)
+	}
+	return apierr.FromResponse(Provider, operation, resp, hint)
+}
@@ -0,0 +1,41 @@
+package crowdin
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"testing"
+)
+
+func TestZipEntryLocale(t *testing.T) {
+	stringsFileRegex, err := compileStringsFileRegex("strings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		wantLocale string
+		wantOk     bool
+		wantDir    string
+	}{
+		{"de/strings.xml", "de", true, "values-de"},
+		{"pt-BR/strings.xml", "pt-BR", true, "values-pt-rBR"},
+		{"es-419/strings.xml", "es-419", true, "values-es-r419"},
+		{"zh-Hans/strings.xml", "zh-Hans", true, "values-b+zh+Hans"},
+		{"es-419/other.xml", "", false, ""},
+		{"419/strings.xml", "", false, ""},
+		{"es-419/nested/strings.xml", "", false, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			locale, ok := zipEntryLocale(stringsFileRegex, test.name)
+			if locale != test.wantLocale || ok != test.wantOk {
+				t.Fatalf("got (%q, %v), want (%q, %v)", locale, ok, test.wantLocale, test.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if dir := resources.ValuesDir(resources.LocaleFromLanguageTag(locale)); dir != test.wantDir {
+				t.Errorf("got the directory %q, want %q", dir, test.wantDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"sort"
+)
+
+// The kinds of the differences between two locales.
+const (
+	// The resource is declared in the locale compared against, but not in the locale.
+	Missing = "missing"
+	// The resource is declared in the locale, but not in the locale compared against.
+	Extra = "extra"
+	// The resource has a different text in the locales.
+	Changed = "changed"
+)
+
+// A difference of a single resource between two locales.
+type Difference struct {
+	Key string
+	// One of `Missing`, `Extra` or `Changed`.
+	Kind string
+	// The text in the locale; empty if it is missing.
+	Text string `json:",omitempty"`
+	// The text in the locale compared against; empty if it is extra.
+	AgainstText string `json:",omitempty"`
+}
+
+// The differences between the resources of two locales, sorted by the key.
+type Result struct {
+	Locale      string
+	Against     string
+	Differences []Difference
+}
+
+// Returns the number of the differences of the `kind`.
+func (r *Result) Count(kind string) int {
+	count := 0
+	for _, d := range r.Differences {
+		if d.Kind == kind {
+			count += 1
+		}
+	}
+	return count
+}
+
+// Compares the resources of the `locale` with the ones of the `against` locale (the base locale if empty).
+// The plural items and the string-array items are compared as one text (see `resources.Resources.Texts`).
+// The non-translatable resources of the `against` locale are not reported as missing.
+func Compare(resDir, stringsFilename, locale, against string) (*Result, error) {
+	res, err := resources.Parse(resDir, locale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	againstRes, err := resources.Parse(resDir, against, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	texts := res.Texts()
+	againstTexts := againstRes.Texts()
+
+	result := &Result{Locale: locale, Against: against}
+	for key, againstText := range againstTexts {
+		text, ok := texts[key]
+		if !ok {
+			if againstRes.IsTranslatable(key) {
+				result.Differences = append(result.Differences, Difference{key, Missing, "", againstText})
+			}
+		} else if text != againstText {
+			result.Differences = append(result.Differences, Difference{key, Changed, text, againstText})
+		}
+	}
+	for key, text := range texts {
+		if _, ok := againstTexts[key]; !ok {
+			result.Differences = append(result.Differences, Difference{key, Extra, text, ""})
+		}
+	}
+	sort.Slice(result.Differences, func(i, j int) bool {
+		return result.Differences[i].Key < result.Differences[j].Key
+	})
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Runs git with the `args` in the `dir` directory and returns its standard output.
+func run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String())))
+	}
+	return out, nil
+}
+
+// Returns the content of the file at `path` (relative to `dir`) in the revision `ref`.
+// If `ref` is empty, the file is read from the working tree.
+func Show(dir, ref, path string) ([]byte, error) {
+	if len(ref) == 0 {
+		return ioutil.ReadFile(filepath.Join(dir, path))
+	}
+	return run(dir, "show", fmt.Sprintf("%s:./%s", ref, filepath.ToSlash(path)))
+}
+
+// Returns an error if the revision `ref` is not a commit of the repository of the `dir` directory.
+func Verify(dir, ref string) error {
+	_, err := run(dir, "rev-parse", "--verify", ref+"^{commit}")
+	return err
+}
+
+// Returns true if the file at `path` (relative to `dir`) exists in the revision `ref`,
+// or in the working tree if `ref` is empty.
+func Exists(dir, ref, path string) bool {
+	_, err := Show(dir, ref, path)
+	return err == nil
+}
+
+// A commit that changed a file.
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+}
+
+// Returns the commits that changed the file at `path` (relative to `dir`), from the oldest to the newest.
+func Log(dir, path string) ([]Commit, error) {
+	out, err := run(dir, "log", "--reverse", "--format=%H%x1f%an%x1f%aI%x1f%s", "--", filepath.ToSlash(path))
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, Commit{fields[0], fields[1], date, fields[3]})
+	}
+	return commits, nil
+}
+
+// Returns the paths (relative to `dir`) of the files under `dir` that changed since the revision `ref`,
+// including the uncommitted changes and the untracked files.
+func ChangedFiles(dir, ref string) ([]string, error) {
+	changed, err := run(dir, "diff", "--name-only", "--relative", ref, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := run(dir, "ls-files", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, line := range strings.Split(string(changed)+"\n"+string(untracked), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			paths = append(paths, filepath.FromSlash(line))
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,94 @@
+package glossary
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The project terminology, read from a JSON file like:
+// {"Settings": {"de": ["Einstellungen"], "pl": ["Ustawienia", "Ustawieniach"]}, "Inbox": {...}}
+// Maps the term of the base language to the approved translations keyed by the locale (e.g. "pt-rBR") or the language.
+type Glossary map[string]map[string][]string
+
+// Reads the glossary from the JSON file at `path`.
+func Load(path string) (Glossary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var glossary Glossary
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&glossary); err != nil {
+		return nil, err
+	}
+	return glossary, nil
+}
+
+// Returns the sorted terms used (as whole words, regardless of the case) in the text `s`.
+func (g Glossary) TermsIn(s string) []string {
+	lower := strings.ToLower(s)
+	var terms []string
+	for term := range g {
+		if containsWord(lower, strings.ToLower(term)) {
+			terms = append(terms, term)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// Returns true if `s` contains the `word`, which is not a part of a longer word.
+func containsWord(s, word string) bool {
+	if len(word) == 0 {
+		return false
+	}
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// Returns the approved translations of the `term` for the `locale` (e.g. "pt-rBR"), or for its language (e.g. "pt").
+// Returns nil if the glossary does not have the translations for the locale.
+func (g Glossary) Approved(term, locale string) []string {
+	translations := g[term]
+	if approved, ok := translations[locale]; ok {
+		return approved
+	}
+	language := strings.TrimPrefix(locale, "b+")
+	if i := strings.IndexAny(language, "-+"); i >= 0 {
+		language = language[:i]
+	}
+	return translations[language]
+}
+
+// Returns true if the text `s` uses any of the `approved` translations, regardless of the case.
+// The translations are matched as substrings, so a stem (e.g. "Ustawie") accepts its inflected forms.
+func Uses(s string, approved []string) bool {
+	lower := strings.ToLower(s)
+	for _, translation := range approved {
+		if strings.Contains(lower, strings.ToLower(translation)) {
+			return true
+		}
+	}
+	return false
+}
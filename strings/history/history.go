@@ -0,0 +1,109 @@
+package history
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/git"
+	"github.com/armatys/android-tools/strings/resources"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// A change of the value of a key in a strings file.
+type Change struct {
+	// The commit with the change; nil for the uncommitted change in the working tree.
+	Commit *git.Commit
+	// The text of the key after the change.
+	Value string
+	// True if the key was removed from the file.
+	Removed bool
+}
+
+// The changes of a key in a single strings file.
+type FileHistory struct {
+	// The short path of the file, e.g. "values-de/strings.xml".
+	Path    string
+	Changes []Change
+}
+
+// Returns the history of the `key` in the base strings file and in the locale files found in `resDir`,
+// based on the git log of the files.
+func KeyHistory(resDir, baseLocale, stringsFilename, key string) ([]FileHistory, error) {
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	basePath := filepath.Join(resDir, resources.ValuesDir(baseLocale), stringsFilename)
+	paths = append([]string{basePath}, paths...)
+
+	var histories []FileHistory
+	for _, path := range paths {
+		shortPath := resources.ShortPath(resDir, path)
+		commits, err := git.Log(resDir, shortPath)
+		if err != nil {
+			return nil, err
+		}
+		history := FileHistory{Path: shortPath}
+		exists := false
+		previous := ""
+		addChange := func(commit *git.Commit, data []byte) error {
+			res, err := resources.ParseData(data)
+			if err != nil {
+				return err
+			}
+			value, ok := res.Texts()[key]
+			if ok && (!exists || value != previous) {
+				history.Changes = append(history.Changes, Change{commit, value, false})
+			} else if !ok && exists {
+				history.Changes = append(history.Changes, Change{commit, "", true})
+			}
+			exists, previous = ok, value
+			return nil
+		}
+		for i := range commits {
+			data, err := git.Show(resDir, commits[i].Hash, shortPath)
+			if err != nil {
+				// The file was deleted in the commit.
+				data = []byte("<resources/>")
+			}
+			if err := addChange(&commits[i], data); err != nil {
+				return nil, err
+			}
+		}
+		data, err := git.Show(resDir, "", shortPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := addChange(nil, data); err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// Writes the histories as a plain text, one change per line.
+func WriteText(w io.Writer, key string, histories []FileHistory) error {
+	var doc strings.Builder
+	doc.WriteString(fmt.Sprintf("History of '%s'\n", key))
+	for _, history := range histories {
+		doc.WriteString(fmt.Sprintf("\n%s\n", history.Path))
+		if len(history.Changes) == 0 {
+			doc.WriteString("  (never declared)\n")
+			continue
+		}
+		for _, change := range history.Changes {
+			when := "uncommitted"
+			if c := change.Commit; c != nil {
+				when = fmt.Sprintf("%s %.8s %s (%s)", c.Date.Format("2006-01-02"), c.Hash, c.Author, c.Subject)
+			}
+			if change.Removed {
+				doc.WriteString(fmt.Sprintf("  %s\n    removed\n", when))
+			} else {
+				doc.WriteString(fmt.Sprintf("  %s\n    %q\n", when, change.Value))
+			}
+		}
+	}
+	_, err := io.WriteString(w, doc.String())
+	return err
+}
@@ -0,0 +1,222 @@
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/armatys/android-tools/strings/report"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The configuration of the issue filing, read from a JSON file like:
+// {"Tracker": "github", "Project": "owner/repo", "Token": "...", "MinRuns": 3, "StatePath": "build/issues-state.json"}
+type Config struct {
+	// "github", "gitlab" or "jira".
+	Tracker string
+	// The base URL of the tracker API; defaults to the public GitHub or GitLab API (required for Jira).
+	URL string
+	// The repository ("owner/repo") on GitHub, the project ID or path on GitLab, or the project key in Jira.
+	Project string
+	// The API token; overridden by the environment variable of the tracker (see `tokenEnvs`).
+	Token string
+	// The number of consecutive runs in which a finding has to be reported to be filed; defaults to 1.
+	MinRuns int
+	// How the findings are grouped into issues: "locale" (the default, an issue per strings file) or "owner".
+	GroupBy string
+	// The owners of the strings, keyed by the glob pattern of the string names (e.g. {"checkout_*": "payments-team"}).
+	Owners map[string]string
+	// The labels of the opened issues.
+	Labels []string
+	// The ID of the Jira transition that closes an issue.
+	CloseTransition string
+	// The path of the file where the run counts and the opened issues are stored between the runs;
+	// defaults to `DefaultStatePath`.
+	StatePath string
+}
+
+// The environment variables with the API tokens of the trackers, which override the `Token` of the configuration,
+// so that the tokens do not have to be stored in a file.
+var tokenEnvs = map[string]string{
+	"github": "GITHUB_TOKEN",
+	"gitlab": "GITLAB_TOKEN",
+	"jira":   "JIRA_API_TOKEN",
+}
+
+// The default path of the state file.
+const DefaultStatePath = ".android-tools-issues.json"
+
+// The state stored between the runs.
+type State struct {
+	// The number of consecutive runs in which the finding was reported, keyed by the fingerprint.
+	Runs map[string]int
+	// The opened issues, keyed by the group (e.g. "values-de/strings.xml" or the owner).
+	Issues map[string]*Issue
+}
+
+// An issue opened for a group of findings.
+type Issue struct {
+	ID string
+	// The fingerprints of the findings listed in the issue.
+	Fingerprints []string
+}
+
+// Reads the configuration from the JSON file at `path`.
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var config Config
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if len(config.StatePath) == 0 {
+		config.StatePath = DefaultStatePath
+	}
+	if token := os.Getenv(tokenEnvs[config.Tracker]); len(tokenEnvs[config.Tracker]) > 0 && len(token) > 0 {
+		config.Token = token
+	}
+	return &config, nil
+}
+
+// Updates the run counts of the `findings`, opens the issues for the groups with the findings reported
+// in at least `MinRuns` runs, updates the issues whose findings changed, and closes the issues without findings.
+// Returns the number of opened and closed issues.
+func Sync(config *Config, findings []report.Finding) (opened, closed int, err error) {
+	t, err := newTracker(config)
+	if err != nil {
+		return 0, 0, err
+	}
+	state, err := loadState(config.StatePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	runs := make(map[string]int)
+	groups := make(map[string][]report.Finding)
+	for _, f := range findings {
+		if _, ok := runs[f.Fingerprint]; ok {
+			continue
+		}
+		runs[f.Fingerprint] = state.Runs[f.Fingerprint] + 1
+		if runs[f.Fingerprint] >= config.MinRuns {
+			group := config.group(f)
+			groups[group] = append(groups[group], f)
+		}
+	}
+	state.Runs = runs
+
+	for _, group := range sortedGroups(groups, state.Issues) {
+		groupFindings := groups[group]
+		issue := state.Issues[group]
+		switch {
+		case issue == nil && len(groupFindings) > 0:
+			id, err := t.Create(issueTitle(group, groupFindings), issueBody(groupFindings))
+			if err != nil {
+				return opened, closed, err
+			}
+			state.Issues[group] = &Issue{id, fingerprints(groupFindings)}
+			opened += 1
+		case issue != nil && len(groupFindings) == 0:
+			if err := t.Close(issue.ID); err != nil {
+				return opened, closed, err
+			}
+			delete(state.Issues, group)
+			closed += 1
+		case issue != nil && strings.Join(issue.Fingerprints, ",") != strings.Join(fingerprints(groupFindings), ","):
+			if err := t.Update(issue.ID, issueTitle(group, groupFindings), issueBody(groupFindings)); err != nil {
+				return opened, closed, err
+			}
+			issue.Fingerprints = fingerprints(groupFindings)
+		}
+	}
+	return opened, closed, saveState(config.StatePath, state)
+}
+
+// Returns the group of the finding: its file, or the owner of its key ("unowned" if there is no owner).
+func (c *Config) group(f report.Finding) string {
+	if c.GroupBy != "owner" {
+		return f.Path
+	}
+	patterns := make([]string, 0, len(c.Owners))
+	for pattern := range c.Owners {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, f.Key); matched {
+			return c.Owners[pattern]
+		}
+	}
+	return "unowned"
+}
+
+func issueTitle(group string, findings []report.Finding) string {
+	return fmt.Sprintf("Localization: %d problem(s) in %s", len(findings), group)
+}
+
+func issueBody(findings []report.Finding) string {
+	var body strings.Builder
+	body.WriteString("The following problems were reported by the strings validation:\n\n")
+	for _, f := range findings {
+		body.WriteString(fmt.Sprintf("- [%s] %s (`%s`)\n", f.Rule, f.Message, f.Fingerprint))
+	}
+	return body.String()
+}
+
+func fingerprints(findings []report.Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Fingerprint)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Returns the groups with the findings or with the opened issues, sorted.
+func sortedGroups(groups map[string][]report.Finding, issues map[string]*Issue) []string {
+	var result []string
+	for group := range groups {
+		result = append(result, group)
+	}
+	for group := range issues {
+		if _, ok := groups[group]; !ok {
+			result = append(result, group)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func loadState(path string) (*State, error) {
+	state := &State{make(map[string]int), make(map[string]*Issue)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Runs == nil {
+		state.Runs = make(map[string]int)
+	}
+	if state.Issues == nil {
+		state.Issues = make(map[string]*Issue)
+	}
+	return state, nil
+}
+
+func saveState(path string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,171 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// An issue tracker.
+type tracker interface {
+	// Opens an issue and returns its ID.
+	Create(title, body string) (string, error)
+	// Replaces the title and the description of the issue.
+	Update(id, title, body string) error
+	Close(id string) error
+}
+
+// Returns the tracker configured in the `config`.
+func newTracker(config *Config) (tracker, error) {
+	client := &apiClient{strings.TrimSuffix(config.URL, "/"), config.Token, config.Tracker}
+	switch config.Tracker {
+	case "github":
+		if len(client.baseURL) == 0 {
+			client.baseURL = "https://api.github.com"
+		}
+		return &githubTracker{client, config.Project, config.Labels}, nil
+	case "gitlab":
+		if len(client.baseURL) == 0 {
+			client.baseURL = "https://gitlab.com/api/v4"
+		}
+		return &gitlabTracker{client, config.Project, config.Labels}, nil
+	case "jira":
+		if len(client.baseURL) == 0 {
+			return nil, errors.New("The URL of the Jira instance is required.")
+		}
+		return &jiraTracker{client, config.Project, config.Labels, config.CloseTransition}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("The issue tracker '%s' is not supported.", config.Tracker))
+}
+
+type apiClient struct {
+	baseURL string
+	token   string
+	tracker string
+}
+
+// Sends the `request` (encoded as JSON) and decodes the JSON response into `response` (if not nil).
+func (c *apiClient) call(method, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch c.tracker {
+	case "gitlab":
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New(fmt.Sprintf("%s %s responded with %s.", method, c.baseURL+path, resp.Status))
+	}
+	if response == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+type githubTracker struct {
+	client *apiClient
+	// The repository, e.g. "owner/repo".
+	repo   string
+	labels []string
+}
+
+func (g *githubTracker) Create(title, body string) (string, error) {
+	var created struct{ Number int }
+	request := map[string]interface{}{"title": title, "body": body, "labels": g.labels}
+	if err := g.client.call("POST", fmt.Sprintf("/repos/%s/issues", g.repo), request, &created); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(created.Number), nil
+}
+
+func (g *githubTracker) Update(id, title, body string) error {
+	return g.client.call("PATCH", fmt.Sprintf("/repos/%s/issues/%s", g.repo, id), map[string]string{"title": title, "body": body}, nil)
+}
+
+func (g *githubTracker) Close(id string) error {
+	return g.client.call("PATCH", fmt.Sprintf("/repos/%s/issues/%s", g.repo, id), map[string]string{"state": "closed"}, nil)
+}
+
+type gitlabTracker struct {
+	client *apiClient
+	// The ID or the path of the project, e.g. "group/project".
+	project string
+	labels  []string
+}
+
+func (g *gitlabTracker) path(suffix string) string {
+	return fmt.Sprintf("/projects/%s/issues%s", url.PathEscape(g.project), suffix)
+}
+
+func (g *gitlabTracker) Create(title, body string) (string, error) {
+	var created struct {
+		IID int `json:"iid"`
+	}
+	request := map[string]string{"title": title, "description": body, "labels": strings.Join(g.labels, ",")}
+	if err := g.client.call("POST", g.path(""), request, &created); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(created.IID), nil
+}
+
+func (g *gitlabTracker) Update(id, title, body string) error {
+	return g.client.call("PUT", g.path("/"+id), map[string]string{"title": title, "description": body}, nil)
+}
+
+func (g *gitlabTracker) Close(id string) error {
+	return g.client.call("PUT", g.path("/"+id), map[string]string{"state_event": "close"}, nil)
+}
+
+type jiraTracker struct {
+	client *apiClient
+	// The key of the project, e.g. "L10N".
+	project string
+	labels  []string
+	// The ID of the transition that closes an issue.
+	closeTransition string
+}
+
+func (j *jiraTracker) Create(title, body string) (string, error) {
+	var created struct{ Key string }
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": "Task"},
+		"summary":     title,
+		"description": body,
+		"labels":      j.labels,
+	}
+	if err := j.client.call("POST", "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+func (j *jiraTracker) Update(id, title, body string) error {
+	fields := map[string]string{"summary": title, "description": body}
+	return j.client.call("PUT", "/rest/api/2/issue/"+id, map[string]interface{}{"fields": fields}, nil)
+}
+
+func (j *jiraTracker) Close(id string) error {
+	if len(j.closeTransition) == 0 {
+		return errors.New("The Jira transition closing the issues is not configured.")
+	}
+	request := map[string]interface{}{"transition": map[string]string{"id": j.closeTransition}}
+	return j.client.call("POST", "/rest/api/2/issue/"+id+"/transitions", request, nil)
+}
@@ -0,0 +1,109 @@
+package locales
+
+// The display names of the languages, scripts and regions, taken from the CLDR data (https://cldr.unicode.org),
+// keyed by the display language and then by the language code (ISO 639), script code (ISO 15924) or region code (ISO 3166).
+
+var languageNames = map[string]map[string]string{
+	"en": {
+		"af": "Afrikaans", "am": "Amharic", "ar": "Arabic", "az": "Azerbaijani", "be": "Belarusian", "bg": "Bulgarian",
+		"bn": "Bangla", "bs": "Bosnian", "ca": "Catalan", "cs": "Czech", "da": "Danish", "de": "German",
+		"el": "Greek", "en": "English", "es": "Spanish", "et": "Estonian", "eu": "Basque", "fa": "Persian",
+		"fi": "Finnish", "fil": "Filipino", "fr": "French", "gl": "Galician", "gu": "Gujarati", "he": "Hebrew",
+		"hi": "Hindi", "hr": "Croatian", "hu": "Hungarian", "hy": "Armenian", "id": "Indonesian", "in": "Indonesian",
+		"is": "Icelandic", "it": "Italian", "iw": "Hebrew", "ja": "Japanese", "ka": "Georgian", "kk": "Kazakh",
+		"km": "Khmer", "kn": "Kannada", "ko": "Korean", "lt": "Lithuanian", "lv": "Latvian", "mk": "Macedonian",
+		"ml": "Malayalam", "mn": "Mongolian", "mr": "Marathi", "ms": "Malay", "my": "Burmese", "nb": "Norwegian Bokmål",
+		"ne": "Nepali", "nl": "Dutch", "no": "Norwegian", "pa": "Punjabi", "pl": "Polish", "pt": "Portuguese",
+		"ro": "Romanian", "ru": "Russian", "si": "Sinhala", "sk": "Slovak", "sl": "Slovenian", "sq": "Albanian",
+		"sr": "Serbian", "sv": "Swedish", "sw": "Swahili", "ta": "Tamil", "te": "Telugu", "th": "Thai",
+		"tl": "Tagalog", "tr": "Turkish", "uk": "Ukrainian", "ur": "Urdu", "uz": "Uzbek", "vi": "Vietnamese",
+		"zh": "Chinese", "zu": "Zulu",
+	},
+	"de": {
+		"ar": "Arabisch", "bg": "Bulgarisch", "ca": "Katalanisch", "cs": "Tschechisch", "da": "Dänisch", "de": "Deutsch",
+		"el": "Griechisch", "en": "Englisch", "es": "Spanisch", "et": "Estnisch", "fa": "Persisch", "fi": "Finnisch",
+		"fr": "Französisch", "he": "Hebräisch", "hi": "Hindi", "hr": "Kroatisch", "hu": "Ungarisch", "id": "Indonesisch",
+		"in": "Indonesisch", "it": "Italienisch", "iw": "Hebräisch", "ja": "Japanisch", "ko": "Koreanisch", "lt": "Litauisch",
+		"lv": "Lettisch", "ms": "Malaiisch", "nb": "Norwegisch (Bokmål)", "nl": "Niederländisch", "no": "Norwegisch", "pl": "Polnisch",
+		"pt": "Portugiesisch", "ro": "Rumänisch", "ru": "Russisch", "sk": "Slowakisch", "sl": "Slowenisch", "sr": "Serbisch",
+		"sv": "Schwedisch", "th": "Thailändisch", "tr": "Türkisch", "uk": "Ukrainisch", "vi": "Vietnamesisch", "zh": "Chinesisch",
+	},
+	"es": {
+		"ar": "árabe", "bg": "búlgaro", "ca": "catalán", "cs": "checo", "da": "danés", "de": "alemán",
+		"el": "griego", "en": "inglés", "es": "español", "et": "estonio", "fa": "persa", "fi": "finés",
+		"fr": "francés", "he": "hebreo", "hi": "hindi", "hr": "croata", "hu": "húngaro", "id": "indonesio",
+		"in": "indonesio", "it": "italiano", "iw": "hebreo", "ja": "japonés", "ko": "coreano", "lt": "lituano",
+		"lv": "letón", "ms": "malayo", "nb": "noruego bokmal", "nl": "neerlandés", "no": "noruego", "pl": "polaco",
+		"pt": "portugués", "ro": "rumano", "ru": "ruso", "sk": "eslovaco", "sl": "esloveno", "sr": "serbio",
+		"sv": "sueco", "th": "tailandés", "tr": "turco", "uk": "ucraniano", "vi": "vietnamita", "zh": "chino",
+	},
+	"fr": {
+		"ar": "arabe", "bg": "bulgare", "ca": "catalan", "cs": "tchèque", "da": "danois", "de": "allemand",
+		"el": "grec", "en": "anglais", "es": "espagnol", "et": "estonien", "fa": "persan", "fi": "finnois",
+		"fr": "français", "he": "hébreu", "hi": "hindi", "hr": "croate", "hu": "hongrois", "id": "indonésien",
+		"in": "indonésien", "it": "italien", "iw": "hébreu", "ja": "japonais", "ko": "coréen", "lt": "lituanien",
+		"lv": "letton", "ms": "malais", "nb": "norvégien bokmål", "nl": "néerlandais", "no": "norvégien", "pl": "polonais",
+		"pt": "portugais", "ro": "roumain", "ru": "russe", "sk": "slovaque", "sl": "slovène", "sr": "serbe",
+		"sv": "suédois", "th": "thaï", "tr": "turc", "uk": "ukrainien", "vi": "vietnamien", "zh": "chinois",
+	},
+	"pl": {
+		"ar": "arabski", "bg": "bułgarski", "ca": "kataloński", "cs": "czeski", "da": "duński", "de": "niemiecki",
+		"el": "grecki", "en": "angielski", "es": "hiszpański", "et": "estoński", "fa": "perski", "fi": "fiński",
+		"fr": "francuski", "he": "hebrajski", "hi": "hindi", "hr": "chorwacki", "hu": "węgierski", "id": "indonezyjski",
+		"in": "indonezyjski", "it": "włoski", "iw": "hebrajski", "ja": "japoński", "ko": "koreański", "lt": "litewski",
+		"lv": "łotewski", "ms": "malajski", "nb": "norweski (bokmål)", "nl": "niderlandzki", "no": "norweski", "pl": "polski",
+		"pt": "portugalski", "ro": "rumuński", "ru": "rosyjski", "sk": "słowacki", "sl": "słoweński", "sr": "serbski",
+		"sv": "szwedzki", "th": "tajski", "tr": "turecki", "uk": "ukraiński", "vi": "wietnamski", "zh": "chiński",
+	},
+}
+
+var scriptNames = map[string]map[string]string{
+	"en": {"Arab": "Arabic", "Cyrl": "Cyrillic", "Hans": "Simplified", "Hant": "Traditional", "Latn": "Latin"},
+	"de": {"Arab": "Arabisch", "Cyrl": "Kyrillisch", "Hans": "vereinfacht", "Hant": "traditionell", "Latn": "Lateinisch"},
+	"es": {"Arab": "árabe", "Cyrl": "cirílico", "Hans": "simplificado", "Hant": "tradicional", "Latn": "latino"},
+	"fr": {"Arab": "arabe", "Cyrl": "cyrillique", "Hans": "simplifié", "Hant": "traditionnel", "Latn": "latin"},
+	"pl": {"Arab": "arabskie", "Cyrl": "cyrylica", "Hans": "uproszczone", "Hant": "tradycyjne", "Latn": "łacińskie"},
+}
+
+var regionNames = map[string]map[string]string{
+	"en": {
+		"419": "Latin America", "AR": "Argentina", "AT": "Austria", "AU": "Australia", "BE": "Belgium", "BR": "Brazil",
+		"CA": "Canada", "CH": "Switzerland", "CL": "Chile", "CN": "China", "CO": "Colombia", "DE": "Germany",
+		"ES": "Spain", "FR": "France", "GB": "United Kingdom", "HK": "Hong Kong", "IE": "Ireland", "IN": "India",
+		"IT": "Italy", "JP": "Japan", "KR": "South Korea", "MX": "Mexico", "NL": "Netherlands", "NZ": "New Zealand",
+		"PE": "Peru", "PL": "Poland", "PT": "Portugal", "RU": "Russia", "SG": "Singapore", "TW": "Taiwan",
+		"UA": "Ukraine", "US": "United States", "VE": "Venezuela", "ZA": "South Africa",
+	},
+	"de": {
+		"419": "Lateinamerika", "AR": "Argentinien", "AT": "Österreich", "AU": "Australien", "BE": "Belgien", "BR": "Brasilien",
+		"CA": "Kanada", "CH": "Schweiz", "CL": "Chile", "CN": "China", "CO": "Kolumbien", "DE": "Deutschland",
+		"ES": "Spanien", "FR": "Frankreich", "GB": "Vereinigtes Königreich", "HK": "Hongkong", "IE": "Irland", "IN": "Indien",
+		"IT": "Italien", "JP": "Japan", "KR": "Südkorea", "MX": "Mexiko", "NL": "Niederlande", "NZ": "Neuseeland",
+		"PE": "Peru", "PL": "Polen", "PT": "Portugal", "RU": "Russland", "SG": "Singapur", "TW": "Taiwan",
+		"UA": "Ukraine", "US": "Vereinigte Staaten", "VE": "Venezuela", "ZA": "Südafrika",
+	},
+	"es": {
+		"419": "Latinoamérica", "AR": "Argentina", "AT": "Austria", "AU": "Australia", "BE": "Bélgica", "BR": "Brasil",
+		"CA": "Canadá", "CH": "Suiza", "CL": "Chile", "CN": "China", "CO": "Colombia", "DE": "Alemania",
+		"ES": "España", "FR": "Francia", "GB": "Reino Unido", "HK": "Hong Kong", "IE": "Irlanda", "IN": "India",
+		"IT": "Italia", "JP": "Japón", "KR": "Corea del Sur", "MX": "México", "NL": "Países Bajos", "NZ": "Nueva Zelanda",
+		"PE": "Perú", "PL": "Polonia", "PT": "Portugal", "RU": "Rusia", "SG": "Singapur", "TW": "Taiwán",
+		"UA": "Ucrania", "US": "Estados Unidos", "VE": "Venezuela", "ZA": "Sudáfrica",
+	},
+	"fr": {
+		"419": "Amérique latine", "AR": "Argentine", "AT": "Autriche", "AU": "Australie", "BE": "Belgique", "BR": "Brésil",
+		"CA": "Canada", "CH": "Suisse", "CL": "Chili", "CN": "Chine", "CO": "Colombie", "DE": "Allemagne",
+		"ES": "Espagne", "FR": "France", "GB": "Royaume-Uni", "HK": "Hong Kong", "IE": "Irlande", "IN": "Inde",
+		"IT": "Italie", "JP": "Japon", "KR": "Corée du Sud", "MX": "Mexique", "NL": "Pays-Bas", "NZ": "Nouvelle-Zélande",
+		"PE": "Pérou", "PL": "Pologne", "PT": "Portugal", "RU": "Russie", "SG": "Singapour", "TW": "Taïwan",
+		"UA": "Ukraine", "US": "États-Unis", "VE": "Venezuela", "ZA": "Afrique du Sud",
+	},
+	"pl": {
+		"419": "Ameryka Łacińska", "AR": "Argentyna", "AT": "Austria", "AU": "Australia", "BE": "Belgia", "BR": "Brazylia",
+		"CA": "Kanada", "CH": "Szwajcaria", "CL": "Chile", "CN": "Chiny", "CO": "Kolumbia", "DE": "Niemcy",
+		"ES": "Hiszpania", "FR": "Francja", "GB": "Wielka Brytania", "HK": "Hongkong", "IE": "Irlandia", "IN": "Indie",
+		"IT": "Włochy", "JP": "Japonia", "KR": "Korea Południowa", "MX": "Meksyk", "NL": "Holandia", "NZ": "Nowa Zelandia",
+		"PE": "Peru", "PL": "Polska", "PT": "Portugalia", "RU": "Rosja", "SG": "Singapur", "TW": "Tajwan",
+		"UA": "Ukraina", "US": "Stany Zjednoczone", "VE": "Wenezuela", "ZA": "Republika Południowej Afryki",
+	},
+}
@@ -0,0 +1,79 @@
+package locales
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+	"strings"
+)
+
+// The display language used when the requested one is not available.
+const DefaultDisplayLanguage = "en"
+
+// Returns the display name of the Android `locale` (e.g. "pt-rBR" or "b+sr+Latn") in the `displayLanguage`,
+// e.g. "Portuguese, Brazil" or "Serbian, Latin". Falls back to the English names,
+// and returns an empty string if the language is not known.
+func DisplayName(locale, displayLanguage string) string {
+	var language, script, region string
+	if strings.HasPrefix(locale, "b+") {
+		subtags := strings.Split(strings.TrimPrefix(locale, "b+"), "+")
+		language = subtags[0]
+		for _, subtag := range subtags[1:] {
+			if len(subtag) == 4 {
+				script = subtag
+			} else if len(subtag) == 2 || len(subtag) == 3 {
+				region = strings.ToUpper(subtag)
+			}
+		}
+	} else {
+		parts := strings.SplitN(locale, "-r", 2)
+		language = parts[0]
+		if len(parts) == 2 {
+			region = parts[1]
+		}
+	}
+
+	name := lookup(languageNames, displayLanguage, strings.ToLower(language))
+	if len(name) == 0 {
+		return ""
+	}
+	details := []string{name}
+	if len(script) > 0 {
+		details = append(details, orCode(lookup(scriptNames, displayLanguage, script), script))
+	}
+	if len(region) > 0 {
+		details = append(details, orCode(lookup(regionNames, displayLanguage, region), region))
+	}
+	return strings.Join(details, ", ")
+}
+
+// Returns the `shortPath` (e.g. "values-pt-rBR/strings.xml") followed by the display name of its locale,
+// e.g. "values-pt-rBR/strings.xml (Portuguese, Brazil)". The path is returned as is,
+// if the locale is not known or the `displayLanguage` is empty.
+func DescribePath(shortPath, displayLanguage string) string {
+	if len(displayLanguage) == 0 {
+		return shortPath
+	}
+	q, err := resources.ParseQualifiers(filepath.Base(filepath.Dir(shortPath)))
+	if err != nil {
+		return shortPath
+	}
+	if name := DisplayName(q.Locale, displayLanguage); len(name) > 0 {
+		return fmt.Sprintf("%s (%s)", shortPath, name)
+	}
+	return shortPath
+}
+
+func lookup(names map[string]map[string]string, displayLanguage, code string) string {
+	if name, ok := names[displayLanguage][code]; ok {
+		return name
+	}
+	return names[DefaultDisplayLanguage][code]
+}
+
+func orCode(name, code string) string {
+	if len(name) > 0 {
+		return name
+	}
+	return code
+}
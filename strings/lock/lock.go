@@ -0,0 +1,107 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The name of the lock file, created in the project directory.
+const Filename = ".android-tools.lock"
+
+// The time after which a lock is considered stale, even if its process is still running.
+const DefaultExpiry = 30 * time.Minute
+
+// The content of the lock file.
+type Info struct {
+	PID      int
+	Hostname string
+	// The action that holds the lock, e.g. "pull".
+	Action  string
+	Expires time.Time
+}
+
+// A lock of a project, held by the current process.
+type Lock struct {
+	path string
+}
+
+// Returns the path of the lock file of the project containing the `resDir` ("res" directory),
+// or of the working directory if `resDir` is empty.
+func PathFor(resDir string) string {
+	if len(resDir) == 0 {
+		return Filename
+	}
+	return filepath.Join(filepath.Dir(filepath.Clean(resDir)), Filename)
+}
+
+// Acquires the lock at `path` for the `action`.
+// A stale lock (expired, or held by a process that is no longer running on this host) is taken over.
+// If `force` is true, a lock held by another process is taken over as well.
+func Acquire(path, action string, force bool) (*Lock, error) {
+	hostname, _ := os.Hostname()
+	info := Info{os.Getpid(), hostname, action, time.Now().Add(DefaultExpiry)}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = file.Write(data)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return &Lock{path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		holder, err := readInfo(path)
+		if err == nil && !force && !holder.isStale(hostname) {
+			return nil, errors.New(fmt.Sprintf("The project is locked by '%s' (PID %d on %s) until %s; use -force to override the lock (%s).", holder.Action, holder.PID, holder.Hostname, holder.Expires.Format(time.RFC3339), path))
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("Could not acquire the lock %s.", path))
+}
+
+// Releases the lock; does nothing if `l` is nil.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	return os.Remove(l.path)
+}
+
+func readInfo(path string) (*Info, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Returns true if the lock has expired, or if its process is not running on this host.
+func (i *Info) isStale(hostname string) bool {
+	if time.Now().After(i.Expires) {
+		return true
+	}
+	return i.Hostname == hostname && !processExists(i.PID)
+}
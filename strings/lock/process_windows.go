@@ -0,0 +1,7 @@
+package lock
+
+// Returns true if a process with the `pid` is running.
+// On Windows the process cannot be checked without opening it, so the lock expires only after its expiry time.
+func processExists(pid int) bool {
+	return true
+}
@@ -0,0 +1,129 @@
+package onboard
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The summary of the new locale.
+type Result struct {
+	// The path of the created strings file.
+	Path string
+	// The names of the strings copied from the base resources.
+	Seeded []string
+	// The number of words of the base strings left for translation.
+	WordCount int
+}
+
+// Creates the strings file of the new `locale` (e.g. "th" or "pt-rBR") in the `resDir`, seeded with the base strings
+// that are the same in every language: the `locked` ones (e.g. brand names) and the ones without any letters
+// (e.g. "%1$s: %2$s"). The strings marked with translatable="false" are not copied, since Android takes them from the base.
+// Fails if the locale already has the strings file.
+func Seed(resDir, baseLocale, filename, locale string, locked []string) (*Result, error) {
+	if !resources.IsLocaleQualifier(locale) {
+		return nil, errors.New(fmt.Sprintf("'%s' is not a locale qualifier (e.g. 'th' or 'pt-rBR').", locale))
+	}
+	base, err := resources.Parse(resDir, baseLocale, filename)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(resDir, resources.ValuesDir(locale), filename)
+	if _, err := os.Stat(path); err == nil {
+		return nil, errors.New(fmt.Sprintf("%s already exists.", path))
+	}
+
+	lockedValues := make(map[string]bool)
+	for _, value := range locked {
+		lockedValues[value] = true
+	}
+	result := &Result{Path: path}
+	var content strings.Builder
+	content.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	content.WriteString(fmt.Sprintf("<resources xmlns:tools=\"http://schemas.android.com/tools\" tools:locale=\"%s\">\n", resources.LanguageTag(locale)))
+	for _, el := range base.Strings {
+		if !el.IsTranslatable() {
+			continue
+		}
+		if lockedValues[el.Value] || !hasLetters(el.Value) {
+			content.WriteString(fmt.Sprintf("    <string name=\"%s\">%s</string>\n", el.Name, el.RawValue))
+			result.Seeded = append(result.Seeded, el.Name)
+			continue
+		}
+		result.WordCount += wordCount(el.Value)
+	}
+	content.WriteString("</resources>\n")
+	for _, el := range base.Plurals {
+		if el.IsTranslatable() {
+			for _, item := range el.Items {
+				result.WordCount += wordCount(item.Value)
+			}
+		}
+	}
+	for _, el := range base.StringArrays {
+		if el.IsTranslatable() {
+			for _, item := range el.Items {
+				result.WordCount += wordCount(item.Value)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := audit.WriteFile(path, []byte(content.String()), "onboard:"+locale); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns true if `s` has any letters, outside of the format specifiers and the resource references.
+func hasLetters(s string) bool {
+	if strings.HasPrefix(strings.TrimSpace(s), "@") {
+		return false
+	}
+	for _, r := range validator.FormatSpecifierRegex.ReplaceAllString(s, "") {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the number of words of the text shown to the user.
+func wordCount(value string) int {
+	count := 0
+	for _, field := range strings.Fields(resources.UnescapeValue(value)) {
+		if hasLetters(field) {
+			count += 1
+		}
+	}
+	return count
+}
+
+// Returns the locales of the strings files in the `resDir`, as listed in the resConfigs of the build.gradle file
+// (e.g. "en", "pt-rBR"); the `baseLocale` is listed as "en" if empty.
+func ResConfigs(resDir, baseLocale, filename string) ([]string, error) {
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, filename)
+	if err != nil {
+		return nil, err
+	}
+	configs := []string{baseLocale}
+	if len(baseLocale) == 0 {
+		configs[0] = "en"
+	}
+	for _, path := range paths {
+		if locale := resources.LocaleFromPath(path); resources.IsLocaleQualifier(locale) {
+			configs = append(configs, locale)
+		}
+	}
+	sort.Strings(configs)
+	return configs, nil
+}
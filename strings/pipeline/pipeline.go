@@ -0,0 +1,332 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/budget"
+	"github.com/armatys/android-tools/strings/crowdin"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches the characters which cannot be used in the names of the environment variables.
+var nonEnvCharRegex = regexp.MustCompile("[^A-Za-z0-9_]")
+
+// The pipelines configuration, read from a JSON file like:
+// {"Pipelines": [{"Name": "staging", "Provider": "crowdin", "Crowdin": {...}, "ResDir": "app/src/main/res"}],
+// "Budgets": {"crowdin": {"PerMinute": 20, "PerDay": 5000}}, "Profiles": {"local": {"Pipelines": [...]}}}
+type Config struct {
+	Pipelines []*Pipeline
+	// The API request limits keyed by the provider, shared by all the processes using the same `BudgetState` file.
+	Budgets map[string]*budget.Limit
+	// The path to the file with the state of the API budgets; defaults to `budget.DefaultStatePath`.
+	BudgetState string
+	// Named profiles (e.g. "ci", "local" or "release") that override the configuration (see `WithProfile`),
+	// selected with the same name as the profiles of the validator configuration.
+	Profiles map[string]*Config
+}
+
+// Describes how the strings of a "res" directory are synchronized with a provider.
+type Pipeline struct {
+	Name string
+	// The name of the provider; currently only "crowdin" is supported.
+	Provider string
+	// The configuration of the "crowdin" provider. The API key and the project name can be set with the environment
+	// variables instead, e.g. CROWDIN_API_KEY, or CROWDIN_API_KEY_STAGING for the pipeline named "staging".
+	Crowdin *crowdin.CrowdinConfig
+	// The path to the "res" directory.
+	ResDir string
+	// The base locale (e.g. "en"); the default "values" directory is used if empty.
+	BaseLocale string
+	// The name of the strings file; defaults to "strings.xml".
+	Filename string
+	// Overrides the mapping of the provider locales to the Android locales, e.g. {"zh-CN": "zh"}.
+	LocaleMap map[string]string
+	// The path to the validator configuration used when pulling the translations; may be empty.
+	ValidatorConfig string
+	// If true, the pulled translations are written without validation; nil (not set) means false.
+	// A pointer, so that a profile can set it back to false.
+	SkipValidation *bool
+}
+
+// Reads the pipelines configuration from the JSON file at `path` and applies the `profile` (if not empty).
+func LoadConfig(path, profile string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	config := &Config{}
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+	if len(profile) > 0 {
+		if config, err = config.WithProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range config.Pipelines {
+		if p.Crowdin != nil {
+			p.Crowdin.ApplyEnv(envSuffix(p.Name))
+		}
+	}
+	return config, nil
+}
+
+// Returns a copy of the configuration with the values overridden by the profile named `name`.
+// The pipelines of the profile override the set fields of the pipelines with the same names,
+// and the other ones are added.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, errors.New(fmt.Sprintf("The profile '%s' is not defined in the pipelines configuration.", name))
+	}
+	merged := *c
+	merged.Pipelines = nil
+	for _, p := range c.Pipelines {
+		copied := *p
+		merged.Pipelines = append(merged.Pipelines, &copied)
+	}
+	for _, override := range profile.Pipelines {
+		var target *Pipeline
+		for _, p := range merged.Pipelines {
+			if p.Name == override.Name {
+				target = p
+			}
+		}
+		if target == nil {
+			copied := *override
+			merged.Pipelines = append(merged.Pipelines, &copied)
+			continue
+		}
+		target.override(override)
+	}
+	if profile.Budgets != nil {
+		merged.Budgets = profile.Budgets
+	}
+	if len(profile.BudgetState) > 0 {
+		merged.BudgetState = profile.BudgetState
+	}
+	return &merged, nil
+}
+
+// Overrides the fields of the pipeline with the ones set in the `profile` pipeline.
+func (p *Pipeline) override(profile *Pipeline) {
+	if len(profile.Provider) > 0 {
+		p.Provider = profile.Provider
+	}
+	if profile.Crowdin != nil {
+		p.Crowdin = profile.Crowdin
+	}
+	if len(profile.ResDir) > 0 {
+		p.ResDir = profile.ResDir
+	}
+	if len(profile.BaseLocale) > 0 {
+		p.BaseLocale = profile.BaseLocale
+	}
+	if len(profile.Filename) > 0 {
+		p.Filename = profile.Filename
+	}
+	if profile.LocaleMap != nil {
+		p.LocaleMap = profile.LocaleMap
+	}
+	if len(profile.ValidatorConfig) > 0 {
+		p.ValidatorConfig = profile.ValidatorConfig
+	}
+	if profile.SkipValidation != nil {
+		p.SkipValidation = profile.SkipValidation
+	}
+}
+
+// Returns true if the pulled translations are written without validation (see `SkipValidation`).
+func (p *Pipeline) skipsValidation() bool {
+	return p.SkipValidation != nil && *p.SkipValidation
+}
+
+// Returns the suffix of the environment variables of the pipeline `name`, e.g. "EU_STAGING" for "eu-staging".
+func envSuffix(name string) string {
+	return strings.ToUpper(nonEnvCharRegex.ReplaceAllString(name, "_"))
+}
+
+// Returns the pipeline named `only`, or all pipelines if `only` is empty.
+func (c *Config) Select(only string) ([]*Pipeline, error) {
+	if len(only) == 0 {
+		return c.Pipelines, nil
+	}
+	for _, p := range c.Pipelines {
+		if p.Name == only {
+			return []*Pipeline{p}, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("The pipeline '%s' is not defined in the configuration.", only))
+}
+
+func (p *Pipeline) filename() string {
+	if len(p.Filename) > 0 {
+		return p.Filename
+	}
+	return "strings.xml"
+}
+
+// Maps the provider locale (e.g. "pt-BR", "es-419" or "zh-Hans") to the Android locale (e.g. "pt-rBR", "es-r419" or "b+zh+Hans").
+func (p *Pipeline) androidLocale(providerLocale string) string {
+	if locale, ok := p.LocaleMap[providerLocale]; ok {
+		return locale
+	}
+	return resources.LocaleFromLanguageTag(providerLocale)
+}
+
+// Maps the Android locale (e.g. "pt-rBR") to the provider locale (e.g. "pt-BR"), the reverse of `androidLocale`.
+func (p *Pipeline) providerLocale(locale string) string {
+	for providerLocale, androidLocale := range p.LocaleMap {
+		if androidLocale == locale {
+			return providerLocale
+		}
+	}
+	return resources.LanguageTag(locale)
+}
+
+// Adds the Android `locale` (e.g. "th") to the target languages of the provider.
+func AddLanguage(p *Pipeline, locale string) error {
+	provider, err := newProvider(p)
+	if err != nil {
+		return err
+	}
+	return provider.AddLanguage(p.providerLocale(locale))
+}
+
+// Downloads the translations from the provider, maps the locales, normalizes and validates the files,
+// and writes them into the "res" directory. Nothing is written if the validation fails.
+// Returns the number of written files.
+func Pull(p *Pipeline) (int, error) {
+	provider, err := newProvider(p)
+	if err != nil {
+		return 0, err
+	}
+	downloaded, err := provider.Download()
+	if err != nil {
+		return 0, err
+	}
+
+	files := make(map[string][]byte)
+	for providerLocale, data := range downloaded {
+		locale := p.androidLocale(providerLocale)
+		if locale == p.BaseLocale {
+			continue
+		}
+		normalized, err := normalize(data)
+		if err != nil {
+			return 0, errors.New(fmt.Sprintf("%s: %s", providerLocale, err.Error()))
+		}
+		files[locale] = normalized
+	}
+
+	if !p.skipsValidation() {
+		if err := p.validate(files); err != nil {
+			return 0, err
+		}
+	}
+
+	locales := make([]string, 0, len(files))
+	for locale := range files {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		path := filepath.Join(p.ResDir, resources.ValuesDir(locale), p.filename())
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return 0, err
+		}
+		if err := audit.WriteFile(path, files[locale], fmt.Sprintf("%s:%s", p.Provider, p.Name)); err != nil {
+			return 0, err
+		}
+	}
+	return len(locales), nil
+}
+
+// Reads the base strings file and uploads it to the provider.
+func Push(p *Pipeline) error {
+	provider, err := newProvider(p)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(p.ResDir, resources.ValuesDir(p.BaseLocale), p.filename())
+	if _, err := resources.ParseFile(path); err != nil {
+		return err
+	}
+	return provider.Upload(path)
+}
+
+// Checks that the `data` is a valid strings file, and normalizes the line endings.
+func normalize(data []byte) ([]byte, error) {
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	if _, err := resources.ParseData(data); err != nil {
+		return nil, err
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	return data, nil
+}
+
+// Validates the `files` (keyed by the locale) against the base strings file,
+// in a temporary copy of the "res" directory.
+func (p *Pipeline) validate(files map[string][]byte) error {
+	var config *validator.Config
+	if len(p.ValidatorConfig) > 0 {
+		var err error
+		if config, err = validator.LoadConfig(p.ValidatorConfig, ""); err != nil {
+			return err
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "pipeline")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	baseData, err := ioutil.ReadFile(filepath.Join(p.ResDir, resources.ValuesDir(p.BaseLocale), p.filename()))
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(tmpDir, resources.ValuesDir(p.BaseLocale), p.filename()), baseData); err != nil {
+		return err
+	}
+	for locale, data := range files {
+		if err := writeFile(filepath.Join(tmpDir, resources.ValuesDir(locale), p.filename()), data); err != nil {
+			return err
+		}
+	}
+
+	errorCount := 0
+	for _, e := range validator.Validate(tmpDir, p.BaseLocale, p.filename(), validator.Options{Config: config}) {
+		if config.SeverityOf(e) != validator.SeverityError {
+			continue
+		}
+		errorCount += 1
+		log.Println(e.Error())
+	}
+	if errorCount > 0 {
+		return errors.New(fmt.Sprintf("The translations pulled by the pipeline '%s' have %d validation errors.", p.Name, errorCount))
+	}
+	return nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,76 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigWithProfile(t *testing.T) {
+	for _, name := range []string{"CROWDIN_PROJECT", "CROWDIN_PROJECT_STAGING"} {
+		t.Setenv(name, "")
+	}
+	path := filepath.Join(t.TempDir(), "pipelines.json")
+	content := `{
+	"Pipelines": [
+		{"Name": "staging", "Provider": "crowdin", "Crowdin": {"ProjectName": "base"}, "ResDir": "app/res", "BaseLocale": "en"},
+		{"Name": "legal", "Provider": "crowdin", "ResDir": "legal/res", "SkipValidation": true}
+	],
+	"BudgetState": "budget.json",
+	"Profiles": {
+		"local": {
+			"Pipelines": [
+				{"Name": "staging", "ResDir": "../app/res", "Crowdin": {"ProjectName": "local"}, "SkipValidation": true},
+				{"Name": "legal", "SkipValidation": false},
+				{"Name": "sandbox", "Provider": "crowdin", "ResDir": "sandbox/res"}
+			],
+			"BudgetState": "local-budget.json"
+		}
+	}
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Pipelines) != 2 || config.Pipelines[0].ResDir != "app/res" || config.BudgetState != "budget.json" {
+		t.Fatalf("the configuration without a profile is not overridden, got %+v", config)
+	}
+
+	config, err = LoadConfig(path, "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		provider string
+		resDir   string
+		skip     bool
+	}{
+		{"staging", "crowdin", "../app/res", true},
+		{"legal", "crowdin", "legal/res", false},
+		{"sandbox", "crowdin", "sandbox/res", false},
+	}
+	if len(config.Pipelines) != len(tests) {
+		t.Fatalf("got %d pipelines, want %d", len(config.Pipelines), len(tests))
+	}
+	for i, test := range tests {
+		p := config.Pipelines[i]
+		if p.Name != test.name || p.Provider != test.provider || p.ResDir != test.resDir || p.skipsValidation() != test.skip {
+			t.Errorf("pipeline %d: got %+v, want %+v", i, *p, test)
+		}
+	}
+	if staging := config.Pipelines[0]; staging.BaseLocale != "en" || staging.Crowdin.ProjectName != "local" {
+		t.Errorf("got the staging pipeline %+v, want the base locale kept and the Crowdin project overridden", *staging)
+	}
+	if config.BudgetState != "local-budget.json" {
+		t.Errorf("got the budget state %q, want %q", config.BudgetState, "local-budget.json")
+	}
+
+	if _, err := LoadConfig(path, "release"); err == nil {
+		t.Error("expected an error for an undefined profile")
+	}
+}
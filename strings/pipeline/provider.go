@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/crowdin"
+)
+
+// A translation management service.
+type Provider interface {
+	// Downloads the translated strings files, keyed by the locale used by the provider (e.g. "pt-BR").
+	Download() (map[string][]byte, error)
+	// Uploads the base strings file at `path`.
+	Upload(path string) error
+	// Adds the `language` (the provider locale, e.g. "pt-BR") to the target languages of the project.
+	AddLanguage(language string) error
+}
+
+type crowdinProvider struct {
+	config *crowdin.CrowdinConfig
+}
+
+func (c *crowdinProvider) Download() (map[string][]byte, error) {
+	return crowdin.DownloadTranslations(c.config)
+}
+
+func (c *crowdinProvider) Upload(path string) error {
+	return crowdin.UploadStrings(c.config, path)
+}
+
+func (c *crowdinProvider) AddLanguage(language string) error {
+	return crowdin.AddLanguage(c.config, language)
+}
+
+// Returns the provider configured for the pipeline `p`.
+func newProvider(p *Pipeline) (Provider, error) {
+	switch p.Provider {
+	case "crowdin":
+		if p.Crowdin == nil {
+			return nil, errors.New(fmt.Sprintf("The pipeline '%s' does not have the Crowdin configuration.", p.Name))
+		}
+		return &crowdinProvider{p.Crowdin}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("The pipeline '%s' has an unknown provider '%s'.", p.Name, p.Provider))
+}
@@ -0,0 +1,140 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A Gradle module with Android resources.
+type Module struct {
+	// The Gradle path of the module, e.g. ":app" or ":feature:checkout" (":" for the root project).
+	Name string
+	// The module directory.
+	Dir string
+	// The "res" directories of the source sets of the module (e.g. "app/src/main/res"): "main" first,
+	// then the other source sets sorted by their names.
+	ResDirs []string
+}
+
+// The directories that are not searched for the modules.
+var skippedDirs = map[string]bool{
+	"build":        true,
+	"node_modules": true,
+	"src":          true,
+}
+
+// Walks the Gradle project tree at `root` and returns the modules (the directories with a build.gradle
+// or build.gradle.kts file) that have any "src/*/res" directories, sorted by their names.
+func Discover(root string) ([]Module, error) {
+	var modules []Module
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != root && (skippedDirs[name] || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+		if !isFile(filepath.Join(path, "build.gradle")) && !isFile(filepath.Join(path, "build.gradle.kts")) {
+			return nil
+		}
+		resDirs, err := filepath.Glob(filepath.Join(path, "src", "*", "res"))
+		if err != nil {
+			return err
+		}
+		var dirs []string
+		for _, resDir := range resDirs {
+			if info, err := os.Stat(resDir); err == nil && info.IsDir() {
+				dirs = append(dirs, resDir)
+			}
+		}
+		if len(dirs) == 0 {
+			return nil
+		}
+		sort.Slice(dirs, func(i, j int) bool {
+			if mainI, mainJ := SourceSet(dirs[i]) == "main", SourceSet(dirs[j]) == "main"; mainI != mainJ {
+				return mainI
+			}
+			return dirs[i] < dirs[j]
+		})
+		modules = append(modules, Module{moduleName(root, path), path, dirs})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+	return modules, nil
+}
+
+// Returns the Gradle path of the module in the `dir` of the project at `root`, e.g. ":feature:checkout".
+func moduleName(root, dir string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return ":"
+	}
+	return ":" + strings.Replace(filepath.ToSlash(rel), "/", ":", -1)
+}
+
+// Returns the name of the source set of the `resDir`, e.g. "main" for "app/src/main/res".
+func SourceSet(resDir string) string {
+	return filepath.Base(filepath.Dir(resDir))
+}
+
+// Returns true if the source set of the `resDir` holds the test resources (e.g. "test" or "androidTestDebug").
+func IsTestSourceSet(resDir string) bool {
+	sourceSet := SourceSet(resDir)
+	return strings.HasPrefix(sourceSet, "test") || strings.HasPrefix(sourceSet, "androidTest")
+}
+
+// Returns the res directories of the module's source sets that the source set of the `resDir` overrides,
+// in the order of their priority: "main", then the source sets whose names make up the name of the source set
+// in the order of the variant name, e.g. "main", "free" and "debug" for "freeDebug" (the product flavors precede
+// the build type in the variant names).
+func (m *Module) LowerSourceSets(resDir string) []string {
+	sourceSet := SourceSet(resDir)
+	if sourceSet == "main" {
+		return nil
+	}
+	type component struct {
+		position int
+		resDir   string
+	}
+	var components []component
+	for _, dir := range m.ResDirs {
+		name := SourceSet(dir)
+		if name == "main" || name == sourceSet || IsTestSourceSet(dir) {
+			continue
+		}
+		if strings.HasPrefix(sourceSet, name) && isWordBoundary(sourceSet, len(name)) {
+			components = append(components, component{0, dir})
+		} else if i := strings.Index(sourceSet, strings.ToUpper(name[:1])+name[1:]); i > 0 && isWordBoundary(sourceSet, i+len(name)) {
+			components = append(components, component{i, dir})
+		}
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].position < components[j].position
+	})
+	lower := []string{filepath.Join(m.Dir, "src", "main", "res")}
+	for _, c := range components {
+		lower = append(lower, c.resDir)
+	}
+	return lower
+}
+
+// Returns true if the camel-case `name` has a word boundary at the byte index `i` (its end or an upper case letter).
+func isWordBoundary(name string, i int) bool {
+	return i == len(name) || (name[i] >= 'A' && name[i] <= 'Z')
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,54 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverSortsModulesAndSourceSets(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{
+		"feature/checkout/build.gradle.kts",
+		"feature/checkout/src/main/res/values/strings.xml",
+		"app/build.gradle",
+		"app/src/release/res/values/strings.xml",
+		"app/src/main/res/values/strings.xml",
+		"app/src/debug/res/values/strings.xml",
+		"app/build/intermediates/src/main/res/values/strings.xml",
+		"lib/build.gradle",
+	} {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modules, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type module struct {
+		name    string
+		resDirs []string
+	}
+	var got []module
+	for _, m := range modules {
+		var resDirs []string
+		for _, dir := range m.ResDirs {
+			rel, _ := filepath.Rel(root, dir)
+			resDirs = append(resDirs, filepath.ToSlash(rel))
+		}
+		got = append(got, module{m.Name, resDirs})
+	}
+	want := []module{
+		{":app", []string{"app/src/main/res", "app/src/debug/res", "app/src/release/res"}},
+		{":feature:checkout", []string{"feature/checkout/src/main/res"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package pseudo
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/brands"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The pseudo-locales generated by `Generate`.
+const (
+	// Accented and expanded text, for finding the hardcoded and truncated strings.
+	LocaleAccented = "en-rXA"
+	// Right-to-left text, for finding the layout problems in the right-to-left locales.
+	LocaleBidi = "ar-rXB"
+)
+
+// The accented replacements of the ASCII letters.
+var accented = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ',
+	'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'ß', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ',
+	'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// The words appended to the accented text, to make it about 30% longer (like the translations often are).
+var paddingWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// Matches the parts of a raw value that are kept as they are: the CDATA sections, the markup (also the escaped one,
+// like "&lt;b>"), the entities, the escape sequences, the format specifiers and the brand variables.
+var keptRegex *regexp.Regexp = regexp.MustCompile("(?s)<!\\[CDATA\\[.*?\\]\\]>|<[^>]*>|&lt;/?[a-zA-Z][^&>]*>|&[a-zA-Z0-9#]+;|\\\\(u[0-9a-fA-F]{4}|.)|%%|" +
+	validator.FormatSpecifierRegex.String() + "|" + brands.VariableRegex.String())
+
+// Transforms the text between the kept parts of a raw value.
+type transform func(text string) string
+
+// Returns the `text` with the letters accented.
+func accent(text string) string {
+	return strings.Map(func(r rune) rune {
+		if a, ok := accented[r]; ok {
+			return a
+		}
+		return r
+	}, text)
+}
+
+// Returns the `text` with every word wrapped in the right-to-left override (RLO and PDF),
+// surrounded by the right-to-left marks, the way the Android pseudo-localization does it.
+func rightToLeft(text string) string {
+	var result strings.Builder
+	for i, word := range strings.Split(text, " ") {
+		if i > 0 {
+			result.WriteString(" ")
+		}
+		if len(word) > 0 {
+			result.WriteString("\u200f\u202e" + word + "\u202c\u200f")
+		}
+	}
+	return result.String()
+}
+
+// Applies the `t` to the text parts of the `rawValue`, keeping the markup, the placeholders and the escapes.
+func pseudolocalize(rawValue string, t transform) string {
+	var result strings.Builder
+	last := 0
+	for _, loc := range keptRegex.FindAllStringIndex(rawValue, -1) {
+		result.WriteString(t(rawValue[last:loc[0]]))
+		result.WriteString(rawValue[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	result.WriteString(t(rawValue[last:]))
+	return result.String()
+}
+
+// Returns the `rawValue` accented, expanded and surrounded with brackets, so that the truncated strings are visible.
+func expand(rawValue string, length int) string {
+	prefix, value, suffix := "", strings.TrimSpace(rawValue), ""
+	if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+		prefix, value, suffix = "\"", value[1:len(value)-1], "\""
+	}
+	var padding []string
+	for paddingLength := 0; paddingLength*10 < length*3; {
+		word := paddingWords[len(padding)%len(paddingWords)]
+		padding = append(padding, word)
+		paddingLength += len(word) + 1
+	}
+	if len(padding) > 0 {
+		value += " " + strings.Join(padding, " ")
+	}
+	return prefix + "[" + pseudolocalize(value, accent) + "]" + suffix
+}
+
+// Returns the pseudo-localized `rawValue` of the `locale`. The values referencing other strings are not changed.
+func valueFor(locale, rawValue, value string) string {
+	if len(strings.TrimSpace(value)) == 0 || strings.HasPrefix(strings.TrimSpace(rawValue), "@") {
+		return rawValue
+	}
+	if locale == LocaleBidi {
+		return pseudolocalize(rawValue, rightToLeft)
+	}
+	return expand(rawValue, len([]rune(value)))
+}
+
+// Generates the string file of the `locale` (`LocaleAccented` or `LocaleBidi`) from the translatable base strings,
+// keeping the markup, the placeholders and the escapes. Returns the path of the written file.
+func Generate(resDir, baseLocale, stringsFilename, locale, outDir string) (string, error) {
+	base, err := resources.Parse(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	content.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	content.WriteString("<!-- Generated by the 'pseudo' action. Do not translate or edit. -->\n")
+	content.WriteString("<resources>\n")
+	for _, s := range base.Strings {
+		if !s.IsTranslatable() {
+			continue
+		}
+		formatted := ""
+		if !s.IsFormatted() {
+			formatted = " formatted=\"false\""
+		}
+		content.WriteString(fmt.Sprintf("    <string name=\"%s\"%s>%s</string>\n", s.Name, formatted, valueFor(locale, s.RawValue, s.Value)))
+	}
+	for _, p := range base.Plurals {
+		if !p.IsTranslatable() {
+			continue
+		}
+		content.WriteString(fmt.Sprintf("    <plurals name=\"%s\">\n", p.Name))
+		for _, item := range p.Items {
+			content.WriteString(fmt.Sprintf("        <item quantity=\"%s\">%s</item>\n", item.Quantity, valueFor(locale, item.RawValue, item.Value)))
+		}
+		content.WriteString("    </plurals>\n")
+	}
+	for _, a := range base.StringArrays {
+		if !a.IsTranslatable() {
+			continue
+		}
+		content.WriteString(fmt.Sprintf("    <string-array name=\"%s\">\n", a.Name))
+		for _, item := range a.Items {
+			content.WriteString(fmt.Sprintf("        <item>%s</item>\n", valueFor(locale, item.RawValue, item.Value)))
+		}
+		content.WriteString("    </string-array>\n")
+	}
+	content.WriteString("</resources>\n")
+
+	path := filepath.Join(outDir, resources.ValuesDir(locale), stringsFilename)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	log.Printf("Writing %s\n", path)
+	return path, audit.WriteFile(path, []byte(content.String()), "pseudo")
+}
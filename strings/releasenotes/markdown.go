@@ -0,0 +1,46 @@
+package releasenotes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var markdownCellEscaper = strings.NewReplacer("|", "\\|", "\n", "<br>")
+
+// Writes the notes as a markdown document.
+func WriteMarkdown(w io.Writer, title string, notes []LocaleNotes) error {
+	var doc strings.Builder
+	doc.WriteString(fmt.Sprintf("# %s\n", title))
+	for _, localeNotes := range notes {
+		if len(localeNotes.DisplayName) > 0 {
+			doc.WriteString(fmt.Sprintf("\n## %s (%s)\n", localeNotes.Locale, localeNotes.DisplayName))
+		} else {
+			doc.WriteString(fmt.Sprintf("\n## %s\n", localeNotes.Locale))
+		}
+		if len(localeNotes.New)+len(localeNotes.Changed)+len(localeNotes.Removed) == 0 {
+			doc.WriteString("\nNo changes.\n")
+			continue
+		}
+		if len(localeNotes.New) > 0 {
+			doc.WriteString(fmt.Sprintf("\n### New strings (%d)\n\n| Key | Text | Context |\n|---|---|---|\n", len(localeNotes.New)))
+			for _, c := range localeNotes.New {
+				doc.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", c.Key, markdownCellEscaper.Replace(c.NewText), markdownCellEscaper.Replace(c.Comment)))
+			}
+		}
+		if len(localeNotes.Changed) > 0 {
+			doc.WriteString(fmt.Sprintf("\n### Changed strings (%d)\n\n| Key | Before | After | Context |\n|---|---|---|---|\n", len(localeNotes.Changed)))
+			for _, c := range localeNotes.Changed {
+				doc.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", c.Key, markdownCellEscaper.Replace(c.OldText), markdownCellEscaper.Replace(c.NewText), markdownCellEscaper.Replace(c.Comment)))
+			}
+		}
+		if len(localeNotes.Removed) > 0 {
+			doc.WriteString(fmt.Sprintf("\n### Removed strings (%d)\n\n", len(localeNotes.Removed)))
+			for _, c := range localeNotes.Removed {
+				doc.WriteString(fmt.Sprintf("- `%s`\n", c.Key))
+			}
+		}
+	}
+	_, err := io.WriteString(w, doc.String())
+	return err
+}
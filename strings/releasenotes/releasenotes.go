@@ -0,0 +1,118 @@
+package releasenotes
+
+import (
+	"github.com/armatys/android-tools/strings/git"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+	"sort"
+)
+
+// A single string change between two revisions.
+type Change struct {
+	Key string
+	// The comment preceding the string in the base file, which gives a context to the translators.
+	Comment string
+	OldText string
+	NewText string
+}
+
+// The changes that need the attention of the translators of a single locale.
+type LocaleNotes struct {
+	Locale string
+	// The human readable name of the locale (e.g. "Portuguese, Brazil"); may be empty.
+	DisplayName string
+	// The strings added to the base file, which are not yet translated to the locale.
+	New []Change
+	// The strings whose base text has changed.
+	Changed []Change
+	// The strings removed from the base file.
+	Removed []Change
+}
+
+// Compares the base strings file in the revisions `fromRef` and `toRef` (the working tree if empty)
+// and returns the change brief for each locale found in `resDir`.
+func Generate(resDir, baseLocale, stringsFilename, fromRef, toRef string) ([]LocaleNotes, error) {
+	basePath := filepath.Join(resources.ValuesDir(baseLocale), stringsFilename)
+	fromTexts, err := readTexts(resDir, fromRef, basePath)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := git.Show(resDir, toRef, basePath)
+	if err != nil {
+		return nil, err
+	}
+	toResources, err := resources.ParseData(toData)
+	if err != nil {
+		return nil, err
+	}
+	toTexts := toResources.Texts()
+	comments, err := resources.ParseComments(toData)
+	if err != nil {
+		return nil, err
+	}
+
+	var added, changed, removed []Change
+	for _, key := range sortedKeys(toTexts) {
+		if !toResources.IsTranslatable(key) {
+			continue
+		}
+		oldText, existed := fromTexts[key]
+		if !existed {
+			added = append(added, Change{key, comments[key], "", toTexts[key]})
+		} else if oldText != toTexts[key] {
+			changed = append(changed, Change{key, comments[key], oldText, toTexts[key]})
+		}
+	}
+	for _, key := range sortedKeys(fromTexts) {
+		if _, exists := toTexts[key]; !exists {
+			removed = append(removed, Change{key, "", fromTexts[key], ""})
+		}
+	}
+
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	var notes []LocaleNotes
+	for _, path := range paths {
+		shortPath := resources.ShortPath(resDir, path)
+		localeTexts, err := readTexts(resDir, toRef, shortPath)
+		if err != nil {
+			return nil, err
+		}
+		localeNotes := LocaleNotes{Locale: resources.LocaleFromPath(shortPath), Changed: changed, Removed: removed}
+		for _, change := range added {
+			if _, translated := localeTexts[change.Key]; !translated {
+				localeNotes.New = append(localeNotes.New, change)
+			}
+		}
+		notes = append(notes, localeNotes)
+	}
+	return notes, nil
+}
+
+// Reads the texts of the strings file at `path` in the revision `ref`.
+// Returns an empty map if the file does not exist in that revision.
+func readTexts(resDir, ref, path string) (map[string]string, error) {
+	if !git.Exists(resDir, ref, path) {
+		return make(map[string]string), nil
+	}
+	data, err := git.Show(resDir, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resources.ParseData(data)
+	if err != nil {
+		return nil, err
+	}
+	return res.Texts(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+package releasenotes
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>%s</sheets>
+</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+%s</Relationships>`
+
+const xlsxSheet = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>%s</sheetData>
+</worksheet>`
+
+// Writes the notes as an XLSX workbook with a sheet for each locale.
+func WriteXLSX(w io.Writer, notes []LocaleNotes) error {
+	var contentTypes, sheets, workbookRels strings.Builder
+	zipWriter := zip.NewWriter(w)
+
+	for i, localeNotes := range notes {
+		sheetNumber := i + 1
+		sheetName := localeNotes.Locale
+		if len(sheetName) == 0 {
+			sheetName = "default"
+		}
+		contentTypes.WriteString(fmt.Sprintf("<Override PartName=\"/xl/worksheets/sheet%d.xml\" ContentType=\"application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml\"/>\n", sheetNumber))
+		sheets.WriteString(fmt.Sprintf("<sheet name=\"%s\" sheetId=\"%d\" r:id=\"rId%d\"/>", xmlEscape(sheetName), sheetNumber, sheetNumber))
+		workbookRels.WriteString(fmt.Sprintf("<Relationship Id=\"rId%d\" Type=\"http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet\" Target=\"worksheets/sheet%d.xml\"/>\n", sheetNumber, sheetNumber))
+
+		rows := [][]string{{"Change", "Key", "Before", "After", "Context"}}
+		for _, c := range localeNotes.New {
+			rows = append(rows, []string{"new", c.Key, "", c.NewText, c.Comment})
+		}
+		for _, c := range localeNotes.Changed {
+			rows = append(rows, []string{"changed", c.Key, c.OldText, c.NewText, c.Comment})
+		}
+		for _, c := range localeNotes.Removed {
+			rows = append(rows, []string{"removed", c.Key, c.OldText, "", ""})
+		}
+		if err := writeZipEntry(zipWriter, fmt.Sprintf("xl/worksheets/sheet%d.xml", sheetNumber), fmt.Sprintf(xlsxSheet, sheetRows(rows))); err != nil {
+			return err
+		}
+	}
+
+	entries := []struct{ name, content string }{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, contentTypes.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, workbookRels.String())},
+	}
+	for _, entry := range entries {
+		if err := writeZipEntry(zipWriter, entry.name, entry.content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+func sheetRows(rows [][]string) string {
+	var data strings.Builder
+	for r, row := range rows {
+		data.WriteString(fmt.Sprintf("<row r=\"%d\">", r+1))
+		for c, cell := range row {
+			data.WriteString(fmt.Sprintf("<c r=\"%c%d\" t=\"inlineStr\"><is><t xml:space=\"preserve\">%s</t></is></c>", 'A'+c, r+1, xmlEscape(cell)))
+		}
+		data.WriteString("</row>")
+	}
+	return data.String()
+}
+
+func writeZipEntry(zipWriter *zip.Writer, name, content string) error {
+	f, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+func xmlEscape(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
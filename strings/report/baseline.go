@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/json"
+	"github.com/armatys/android-tools/strings/validator"
+	"os"
+)
+
+// The fingerprints of the findings accepted in a baseline file, which are not reported again.
+type Baseline map[string]bool
+
+// Reads the baseline file at `path`, written by `WriteBaseline`.
+func LoadBaseline(path string) (Baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var findings []Finding
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&findings); err != nil {
+		return nil, err
+	}
+	baseline := make(Baseline)
+	for _, f := range findings {
+		baseline[f.Fingerprint] = true
+	}
+	return baseline, nil
+}
+
+// Writes the `findings` to the baseline file at `path` (as a JSON report).
+func WriteBaseline(path string, findings []Finding) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return WriteJSON(file, findings)
+}
+
+// Adds the `findings` to the baseline file at `path`, which is created if it does not exist.
+// The findings already in the baseline are not added again.
+func AppendBaseline(path string, findings []Finding) error {
+	var all []Finding
+	if file, err := os.Open(path); err == nil {
+		err = json.NewDecoder(file).Decode(&all)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	baseline := make(Baseline)
+	for _, f := range all {
+		baseline[f.Fingerprint] = true
+	}
+	for _, f := range findings {
+		if !baseline[f.Fingerprint] {
+			baseline[f.Fingerprint] = true
+			all = append(all, f)
+		}
+	}
+	return WriteBaseline(path, all)
+}
+
+// Returns the errors of the `module` (empty for a single res directory) whose findings are not in the baseline.
+// The `DeadlineExceededError` is kept.
+func (b Baseline) Filter(errorList []error, module string, config *validator.Config) []error {
+	var filtered []error
+	for _, e := range errorList {
+		findings := WithModule(Findings([]error{e}, config), module)
+		if len(findings) == 0 || !b[findings[0].Fingerprint] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+)
+
+// The Checkstyle XML report, as read by the CI plugins (e.g. Jenkins Warnings NG or Danger).
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Writes the findings as a Checkstyle XML report, with the files grouped in the order of their first findings.
+// The files are located in the res directories returned by `resDirOf` for the modules of the findings.
+func WriteCheckstyle(w io.Writer, findings []Finding, resDirOf func(module string) string) error {
+	report := checkstyleReport{Version: "8.0"}
+	indices := make(map[string]int)
+	for _, f := range findings {
+		var path string
+		if len(f.Path) > 0 {
+			path = f.filePath(resDirOf(f.Module))
+		}
+		i, ok := indices[path]
+		if !ok {
+			i = len(report.Files)
+			indices[path] = i
+			report.Files = append(report.Files, checkstyleFile{Name: filepath.ToSlash(path)})
+		}
+		report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{f.Line, f.Column, f.Severity, f.Message, "android-tools." + f.Rule})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>String validation report</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.error { color: #b00020; }
+.warning { color: #b26a00; }
+.info { color: #555; }
+.suggestion { color: #555; font-size: smaller; }
+</style>
+</head>
+<body>
+<h1>String validation report</h1>
+`
+
+// Writes the findings as an HTML document with a table.
+func WriteHTML(w io.Writer, findings []Finding) error {
+	var doc strings.Builder
+	doc.WriteString(htmlHeader)
+	if len(findings) == 0 {
+		doc.WriteString("<p>No errors found.</p>\n")
+	} else {
+		doc.WriteString("<table>\n<tr><th>Severity</th><th>File</th><th>Key</th><th>Rule</th><th>Message</th></tr>\n")
+		for _, f := range findings {
+			message := html.EscapeString(f.Message)
+			if s := f.Suggestion; s != nil {
+				review := "approved"
+				if s.NeedsReview {
+					review = "needs review"
+				}
+				message += fmt.Sprintf("<div class=\"suggestion\">Suggestion (%.0f%% match with <code>%s</code> &ldquo;%s&rdquo;, %.0f%% confidence, %s): %s</div>", s.Score*100, html.EscapeString(s.Key), html.EscapeString(s.Source), s.Confidence*100, review, html.EscapeString(s.Translation))
+			}
+			path := f.Path
+			if len(f.Module) > 0 {
+				path = f.Module + " " + path
+			}
+			doc.WriteString(fmt.Sprintf("<tr class=\"%s\"><td>%s</td><td>%s</td><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n", f.Severity, f.Severity, html.EscapeString(path), html.EscapeString(f.Key), f.Rule, message))
+		}
+		doc.WriteString("</table>\n")
+	}
+	doc.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, doc.String())
+	return err
+}
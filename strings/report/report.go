@@ -0,0 +1,114 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// A validation finding, as written in the JSON and HTML reports.
+type Finding struct {
+	// The module of a multi-module project (e.g. ":app"); empty when a single res directory is validated.
+	Module string `json:",omitempty"`
+	// The short path of the file (e.g. "values-de/strings.xml"); empty for errors not related to a resource.
+	Path string
+	// The short path of the file of a file set declaring the resource (see `validator.Position`);
+	// empty if it is the `Path` or unknown.
+	File string `json:",omitempty"`
+	// The line and the column (starting with 1) of the resource in the file; zero if unknown.
+	Line   int `json:",omitempty"`
+	Column int `json:",omitempty"`
+	// The name of the resource.
+	Key string
+	// The ID of the rule, e.g. "markup" or "missing".
+	Rule     string
+	Severity string
+	Message  string
+	// The translation suggested for a missing string; may be nil.
+	Suggestion *validator.Suggestion `json:",omitempty"`
+	// The suggested replacement of the raw value, for the rules with a mechanical fix (see `validator.ValidationError.Fix`).
+	Fix string `json:",omitempty"`
+	// Identifies the finding between the runs (see `fingerprint`).
+	Fingerprint string
+}
+
+// Returns a stable identifier of the finding, based on its rule, file and key
+// (and the message for the findings not related to a key), so the same problem has the same fingerprint in every run.
+func fingerprint(f Finding) string {
+	parts := []string{f.Rule, f.Path, f.Key}
+	if len(f.Module) > 0 {
+		parts = append(parts, f.Module)
+	}
+	if len(f.Key) == 0 {
+		parts = append(parts, f.Message)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Converts the errors returned by the validation to findings, with the severities according to the `config`.
+// The `DeadlineExceededError` is skipped.
+func Findings(errorList []error, config *validator.Config) []Finding {
+	findings := make([]Finding, 0, len(errorList))
+	for _, e := range errorList {
+		finding := Finding{Severity: config.SeverityOf(e), Message: e.Error()}
+		switch t := e.(type) {
+		case *validator.DeadlineExceededError:
+			continue
+		case *validator.ValidationError:
+			finding.Path, finding.Key, finding.Rule = t.Path, t.Key, t.Rule
+			finding.Fix = t.Fix
+		case *validator.ResourceMissingError:
+			finding.Path, finding.Key, finding.Rule = t.Path, t.Key, validator.RuleMissing
+			finding.Suggestion = t.Suggestion
+		case *validator.DanglingReferenceError:
+			finding.Path, finding.Key, finding.Rule = t.Path, t.Key, validator.RuleStringReference
+		}
+		if position := validator.PositionOf(e); position != nil {
+			finding.Line, finding.Column = position.Line, position.Column
+			if position.File != finding.Path {
+				finding.File = position.File
+			}
+		}
+		finding.Fingerprint = fingerprint(finding)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Returns the `findings` of the `module` of a multi-module project, with their fingerprints including the module.
+func WithModule(findings []Finding, module string) []Finding {
+	if len(module) == 0 {
+		return findings
+	}
+	for i := range findings {
+		findings[i].Module = module
+		findings[i].Fingerprint = fingerprint(findings[i])
+	}
+	return findings
+}
+
+// Returns the path of the file of the finding in the `resDir`: the file declaring the resource,
+// or the first file of a file set for the resources not declared in any of its files (e.g. the missing ones).
+func (f *Finding) filePath(resDir string) string {
+	if len(f.File) > 0 {
+		return filepath.Join(resDir, f.File)
+	}
+	path := filepath.Join(resDir, f.Path)
+	if paths, err := resources.ExpandPath(path); err == nil && len(paths) > 0 {
+		return paths[0]
+	}
+	return path
+}
+
+// Writes the findings as a JSON array.
+func WriteJSON(w io.Writer, findings []Finding) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(findings)
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"encoding/json"
+	"github.com/armatys/android-tools/strings/validator"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// The SARIF 2.1.0 log (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html),
+// limited to the properties used by the code scanning tools (e.g. GitHub).
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// The SARIF levels of the severities.
+var sarifLevels = map[string]string{
+	validator.SeverityError:   "error",
+	validator.SeverityWarning: "warning",
+	validator.SeverityInfo:    "note",
+}
+
+// Writes the findings as a SARIF 2.1.0 log. The files are located in the res directories returned by `resDirOf`
+// for the modules of the findings (see `Finding.Module`).
+func WriteSARIF(w io.Writer, findings []Finding, resDirOf func(module string) string) error {
+	rules := make(map[string]bool)
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		rule := f.Rule
+		if len(rule) == 0 {
+			rule = "error"
+		}
+		rules[rule] = true
+		result := sarifResult{
+			RuleID:              rule,
+			Level:               sarifLevels[f.Severity],
+			Message:             sarifMessage{f.Message},
+			PartialFingerprints: map[string]string{"findingFingerprint/v1": f.Fingerprint},
+		}
+		if len(f.Path) > 0 {
+			path := f.filePath(resDirOf(f.Module))
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{filepath.ToSlash(path)}}
+			if f.Line > 0 {
+				location.Region = &sarifRegion{f.Line, f.Column}
+			}
+			result.Locations = []sarifLocation{{location}}
+		}
+		results = append(results, result)
+	}
+
+	var ruleIDs []string
+	for rule := range rules {
+		ruleIDs = append(ruleIDs, rule)
+	}
+	sort.Strings(ruleIDs)
+	driver := sarifDriver{Name: "android-tools strings validator", InformationURI: "https://github.com/armatys/android-tools", Rules: []sarifRule{}}
+	for _, id := range ruleIDs {
+		driver.Rules = append(driver.Rules, sarifRule{id})
+	}
+	log := sarifLog{"https://json.schemastore.org/sarif-2.1.0.json", "2.1.0", []sarifRun{{sarifTool{driver}, results}}}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Returns true if the `stringsFilename` is a comma-separated list (e.g. "strings.xml,plurals.xml")
+// or a glob pattern (e.g. "strings*.xml") of the strings files, which are merged into one set of resources per locale.
+func IsFileSet(stringsFilename string) bool {
+	return strings.ContainsAny(stringsFilename, ",*?[")
+}
+
+// Returns the paths of the existing files of the `path` (e.g. "res/values-de/strings.xml,plurals.xml"),
+// whose filename may be a file set (see `IsFileSet`). A single filename is returned as is, even if the file does not exist.
+func ExpandPath(path string) ([]string, error) {
+	dir, filename := filepath.Split(path)
+	if !IsFileSet(filename) {
+		return []string{path}, nil
+	}
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range strings.Split(filename, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() && !seen[match] {
+				seen[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Returns the resources of all the files, with the declarations in the order of the files.
+func merge(all []*Resources) *Resources {
+	merged := &Resources{ignoreComments: make(map[string][]string), maxLengths: make(map[string]int)}
+	for _, r := range all {
+		if len(merged.ToolsLocale) == 0 {
+			merged.ToolsLocale = r.ToolsLocale
+		}
+		merged.Strings = append(merged.Strings, r.Strings...)
+		merged.Plurals = append(merged.Plurals, r.Plurals...)
+		merged.StringArrays = append(merged.StringArrays, r.StringArrays...)
+		for name, rules := range r.ignoreComments {
+			merged.ignoreComments[name] = append(merged.ignoreComments[name], rules...)
+		}
+		for name, length := range r.maxLengths {
+			merged.maxLengths[name] = length
+		}
+	}
+	merged.reindex()
+	return merged
+}
+
+// Returns the resources `all` merged the way the Android Gradle plugin merges the source sets:
+// the strings, plurals and arrays of the later resources override the ones with the same name in the earlier ones.
+func Override(all []*Resources) *Resources {
+	merged := &Resources{ignoreComments: make(map[string][]string), maxLengths: make(map[string]int)}
+	merged.reindex()
+	for _, r := range all {
+		if len(r.ToolsLocale) > 0 {
+			merged.ToolsLocale = r.ToolsLocale
+		}
+		for _, el := range r.Strings {
+			if i, ok := merged.index.strings[el.Name]; ok {
+				merged.Strings[i] = el
+			} else {
+				merged.index.strings[el.Name] = len(merged.Strings)
+				merged.Strings = append(merged.Strings, el)
+			}
+		}
+		for _, el := range r.Plurals {
+			if i, ok := merged.index.plurals[el.Name]; ok {
+				merged.Plurals[i] = el
+			} else {
+				merged.index.plurals[el.Name] = len(merged.Plurals)
+				merged.Plurals = append(merged.Plurals, el)
+			}
+		}
+		for _, el := range r.StringArrays {
+			if i, ok := merged.index.stringArrays[el.Name]; ok {
+				merged.StringArrays[i] = el
+			} else {
+				merged.index.stringArrays[el.Name] = len(merged.StringArrays)
+				merged.StringArrays = append(merged.StringArrays, el)
+			}
+		}
+		for name, rules := range r.ignoreComments {
+			merged.ignoreComments[name] = rules
+		}
+		for name, length := range r.maxLengths {
+			merged.maxLengths[name] = length
+		}
+	}
+	merged.reindex()
+	return merged
+}
+
+// Returns the names of the strings, plurals and arrays declared in the resources.
+func (r *Resources) Names() map[string]bool {
+	names := make(map[string]bool)
+	for _, el := range r.Strings {
+		names[el.Name] = true
+	}
+	for _, el := range r.Plurals {
+		names[el.Name] = true
+	}
+	for _, el := range r.StringArrays {
+		names[el.Name] = true
+	}
+	return names
+}
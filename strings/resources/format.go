@@ -0,0 +1,278 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The elements containing other elements (e.g. <item>) rather than a value, formatted with one child per line.
+var containerElements = map[string]bool{
+	"resources":         true,
+	"plurals":           true,
+	"string-array":      true,
+	"integer-array":     true,
+	"array":             true,
+	"style":             true,
+	"declare-styleable": true,
+}
+
+// The indentation of one nesting level of the formatted files.
+const formatIndent = "    "
+
+// A node of the XML document being formatted.
+type formatNode struct {
+	// One of xml.StartElement, xml.CharData, xml.Comment, xml.ProcInst or xml.Directive.
+	token    xml.Token
+	children []*formatNode
+	// The CDATA section as it is in the file, if the node is one.
+	cdata string
+}
+
+// Formats the resources file at `path` (see `Format`). Returns true if the file was changed.
+// The change is recorded in the audit log with the `source`.
+func FormatFile(path, source string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	formatted, err := Format(data)
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+	}
+	if bytes.Equal(data, formatted) {
+		return false, nil
+	}
+	return true, audit.WriteFile(path, formatted, source)
+}
+
+// Returns the XML `data` of a resources file in the canonical format: the containers (e.g. <resources> or <plurals>)
+// have one child per line, indented with 4 spaces, with at most one blank line between the children.
+// The "name" attribute goes first (after the namespace declarations), followed by the other attributes sorted by name.
+// The entities are only used for the characters which must be escaped in XML and for the invisible ones (e.g. &#160;).
+// The values of the strings are kept as they are, including the whitespace, the comments, the xliff and HTML markup
+// and the Android escape sequences.
+func Format(data []byte) ([]byte, error) {
+	// the raw tokens are not checked for the matching tags
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	document := &formatNode{}
+	stack := []*formatNode{document}
+	decoder = xml.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.StartElement:
+			node := &formatNode{token: xml.CopyToken(t)}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.CharData:
+			node := &formatNode{token: xml.CopyToken(t)}
+			if raw := string(data[offset:decoder.InputOffset()]); strings.HasPrefix(raw, "<![CDATA[") {
+				node.cdata = raw
+			}
+			parent.children = append(parent.children, node)
+		default:
+			parent.children = append(parent.children, &formatNode{token: xml.CopyToken(t)})
+		}
+	}
+
+	var formatted bytes.Buffer
+	formatted.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	for _, node := range document.children {
+		if inst, ok := node.token.(xml.ProcInst); ok && inst.Target == "xml" {
+			continue
+		}
+		if text, ok := node.token.(xml.CharData); ok && len(node.cdata) == 0 && len(strings.TrimSpace(string(text))) == 0 {
+			continue
+		}
+		writeFormattedNode(&formatted, node, 0)
+		formatted.WriteString("\n")
+	}
+	return formatted.Bytes(), nil
+}
+
+// Writes the `node` at the nesting `level`, without the indentation of its first line.
+func writeFormattedNode(w *bytes.Buffer, node *formatNode, level int) {
+	start, ok := node.token.(xml.StartElement)
+	if !ok || !containerElements[start.Name.Local] || !isBlock(node) {
+		writeInlineNode(w, node)
+		return
+	}
+	writeStartTag(w, start)
+	if len(node.children) == 0 {
+		w.Truncate(w.Len() - 1)
+		w.WriteString("/>")
+		return
+	}
+	written := false
+	blankLine := false
+	for _, child := range node.children {
+		if text, ok := child.token.(xml.CharData); ok && len(child.cdata) == 0 {
+			if strings.Count(string(text), "\n") > 1 && written {
+				blankLine = true
+			}
+			continue
+		}
+		w.WriteString("\n")
+		if blankLine {
+			w.WriteString("\n")
+			blankLine = false
+		}
+		w.WriteString(strings.Repeat(formatIndent, level+1))
+		writeFormattedNode(w, child, level+1)
+		written = true
+	}
+	w.WriteString("\n")
+	w.WriteString(strings.Repeat(formatIndent, level))
+	writeEndTag(w, start.Name)
+}
+
+// Returns true if the children of the `node` can be written one per line,
+// i.e. there is only whitespace between them.
+func isBlock(node *formatNode) bool {
+	for _, child := range node.children {
+		if text, ok := child.token.(xml.CharData); ok && (len(child.cdata) > 0 || len(strings.TrimSpace(string(text))) > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Writes the `node` with its content as it is, only with the tags and the escaping in the canonical format.
+func writeInlineNode(w *bytes.Buffer, node *formatNode) {
+	switch t := node.token.(type) {
+	case xml.StartElement:
+		writeStartTag(w, t)
+		if len(node.children) == 0 {
+			w.Truncate(w.Len() - 1)
+			w.WriteString("/>")
+			return
+		}
+		for _, child := range node.children {
+			writeInlineNode(w, child)
+		}
+		writeEndTag(w, t.Name)
+	case xml.CharData:
+		if len(node.cdata) > 0 {
+			w.WriteString(node.cdata)
+		} else {
+			w.WriteString(escapeFormatted(string(t), false))
+		}
+	case xml.Comment:
+		w.WriteString("<!--")
+		w.Write(t)
+		w.WriteString("-->")
+	case xml.ProcInst:
+		w.WriteString("<?")
+		w.WriteString(t.Target)
+		if len(t.Inst) > 0 {
+			w.WriteString(" ")
+			w.Write(t.Inst)
+		}
+		w.WriteString("?>")
+	case xml.Directive:
+		w.WriteString("<!")
+		w.Write(t)
+		w.WriteString(">")
+	}
+}
+
+// Writes the start tag of the `element`, with the attributes in the canonical order.
+func writeStartTag(w *bytes.Buffer, element xml.StartElement) {
+	attrs := append([]xml.Attr{}, element.Attr...)
+	sort.SliceStable(attrs, func(i, j int) bool {
+		ri, rj := attrRank(attrs[i]), attrRank(attrs[j])
+		if ri != rj || ri < 2 {
+			return ri < rj
+		}
+		return qualifiedName(attrs[i].Name) < qualifiedName(attrs[j].Name)
+	})
+	w.WriteString("<")
+	w.WriteString(qualifiedName(element.Name))
+	for _, attr := range attrs {
+		w.WriteString(fmt.Sprintf(" %s=\"%s\"", qualifiedName(attr.Name), escapeFormatted(attr.Value, true)))
+	}
+	w.WriteString(">")
+}
+
+// Writes the end tag of the element with the `name`.
+func writeEndTag(w *bytes.Buffer, name xml.Name) {
+	w.WriteString("</")
+	w.WriteString(qualifiedName(name))
+	w.WriteString(">")
+}
+
+// Returns the position of the attribute in the canonical order: the namespace declarations, the "name", and the rest.
+func attrRank(attr xml.Attr) int {
+	if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+		return 0
+	}
+	if attr.Name.Space == "" && attr.Name.Local == "name" {
+		return 1
+	}
+	return 2
+}
+
+// Returns the name with its namespace prefix, e.g. "xliff:g".
+func qualifiedName(name xml.Name) string {
+	if len(name.Space) > 0 {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// Escapes the text of an element, or of an attribute if `attr` is true. Only the characters which must be escaped
+// are replaced with the entities, and the invisible characters, so that they are not lost (e.g. "&#160;").
+func escapeFormatted(s string, attr bool) string {
+	var escaped strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '&':
+			escaped.WriteString("&amp;")
+		case r == '<':
+			escaped.WriteString("&lt;")
+		case r == '>' && strings.HasSuffix(s[:i], "]]"):
+			escaped.WriteString("&gt;")
+		case r == '"' && attr:
+			escaped.WriteString("&quot;")
+		case r == '\n' || r == '\t':
+			if attr {
+				escaped.WriteString(fmt.Sprintf("&#%d;", r))
+			} else {
+				escaped.WriteRune(r)
+			}
+		case r == ' ':
+			escaped.WriteRune(r)
+		case !unicode.IsGraphic(r) || unicode.IsSpace(r):
+			escaped.WriteString(fmt.Sprintf("&#%d;", r))
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches the comment suppressing validation rules for the following resource, e.g. <!-- validator:ignore markup newline -->.
+var ignoreCommentRegex *regexp.Regexp = regexp.MustCompile("^validator:ignore\\s+(.+)$")
+
+// Returns the rule IDs listed in the value of the tools:ignore attribute or the validator:ignore comment.
+// The IDs may be separated by commas or spaces.
+func parseIgnoredRules(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// Reads the validator:ignore comments from the `comments` preceding the resources, keyed by the resource name.
+func parseIgnoreComments(comments map[string]string) map[string][]string {
+	ignored := make(map[string][]string)
+	for name, comment := range comments {
+		if match := ignoreCommentRegex.FindStringSubmatch(comment); match != nil {
+			ignored[name] = parseIgnoredRules(match[1])
+		}
+	}
+	return ignored
+}
+
+// Returns true if the rule `ruleId` is suppressed for the resource `name`,
+// either with the tools:ignore attribute, or with the <!-- validator:ignore rule-id --> comment preceding the resource.
+// The "all" ID suppresses every rule.
+func (r *Resources) IsIgnored(name, ruleId string) bool {
+	var ignored []string
+	if el := r.FindString(name); el != nil {
+		ignored = append(ignored, parseIgnoredRules(el.Ignore)...)
+	} else if el := r.FindPlural(name); el != nil {
+		ignored = append(ignored, parseIgnoredRules(el.Ignore)...)
+	} else if el := r.FindStringArray(name); el != nil {
+		ignored = append(ignored, parseIgnoredRules(el.Ignore)...)
+	}
+	ignored = append(ignored, r.ignoreComments[name]...)
+	for _, id := range ignored {
+		if id == ruleId || id == "all" {
+			return true
+		}
+	}
+	return false
+}
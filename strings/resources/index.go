@@ -0,0 +1,65 @@
+package resources
+
+// The positions of the strings, plurals and string-arrays in the `Resources` keyed by their names,
+// so that finding a resource does not scan all of them. Only the first of the resources with the same name is indexed.
+type index struct {
+	strings      map[string]int
+	plurals      map[string]int
+	stringArrays map[string]int
+	// The indexed slices; the index is not used once the slices of the resources are replaced or appended to.
+	indexedStrings      []String
+	indexedPlurals      []Plural
+	indexedStringArrays []StringArray
+}
+
+// Builds the index of the resources. It must be called again after the resources are changed.
+func (r *Resources) reindex() {
+	ix := &index{
+		strings:             make(map[string]int),
+		plurals:             make(map[string]int),
+		stringArrays:        make(map[string]int),
+		indexedStrings:      r.Strings,
+		indexedPlurals:      r.Plurals,
+		indexedStringArrays: r.StringArrays,
+	}
+	for i := len(r.Strings) - 1; i >= 0; i-- {
+		ix.strings[r.Strings[i].Name] = i
+	}
+	for i := len(r.Plurals) - 1; i >= 0; i-- {
+		ix.plurals[r.Plurals[i].Name] = i
+	}
+	for i := len(r.StringArrays) - 1; i >= 0; i-- {
+		ix.stringArrays[r.StringArrays[i].Name] = i
+	}
+	r.index = ix
+}
+
+// Returns the position of the string `name` in `Strings`; `ok` is false if the index cannot be used.
+func (r *Resources) indexOfString(name string) (i int, found, ok bool) {
+	if r.index == nil || len(r.index.indexedStrings) != len(r.Strings) ||
+		(len(r.Strings) > 0 && &r.index.indexedStrings[0] != &r.Strings[0]) {
+		return 0, false, false
+	}
+	i, found = r.index.strings[name]
+	return i, found, true
+}
+
+// Returns the position of the plurals `name` in `Plurals`; `ok` is false if the index cannot be used.
+func (r *Resources) indexOfPlural(name string) (i int, found, ok bool) {
+	if r.index == nil || len(r.index.indexedPlurals) != len(r.Plurals) ||
+		(len(r.Plurals) > 0 && &r.index.indexedPlurals[0] != &r.Plurals[0]) {
+		return 0, false, false
+	}
+	i, found = r.index.plurals[name]
+	return i, found, true
+}
+
+// Returns the position of the string-array `name` in `StringArrays`; `ok` is false if the index cannot be used.
+func (r *Resources) indexOfStringArray(name string) (i int, found, ok bool) {
+	if r.index == nil || len(r.index.indexedStringArrays) != len(r.StringArrays) ||
+		(len(r.StringArrays) > 0 && &r.index.indexedStringArrays[0] != &r.StringArrays[0]) {
+		return 0, false, false
+	}
+	i, found = r.index.stringArrays[name]
+	return i, found, true
+}
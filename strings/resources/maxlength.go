@@ -0,0 +1,27 @@
+package resources
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Matches the maximal length declared in the comment preceding a resource, e.g. <!-- Notification title, maxLength=40 -->.
+var maxLengthCommentRegex *regexp.Regexp = regexp.MustCompile("\\bmaxLength\\s*=\\s*([0-9]+)")
+
+// Reads the maxLength=N comments from the `comments` preceding the resources, keyed by the resource name.
+func parseMaxLengthComments(comments map[string]string) map[string]int {
+	maxLengths := make(map[string]int)
+	for name, comment := range comments {
+		if match := maxLengthCommentRegex.FindStringSubmatch(comment); match != nil {
+			if n, err := strconv.Atoi(match[1]); err == nil {
+				maxLengths[name] = n
+			}
+		}
+	}
+	return maxLengths
+}
+
+// Returns the maximal length of the resource `name` declared with the maxLength=N comment, or zero if not declared.
+func (r *Resources) MaxLength(name string) int {
+	return r.maxLengths[name]
+}
@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// A resource declared differently in two of the merged files.
+type MergeConflict struct {
+	Element string
+	Name    string
+	// The file and the line (starting with 1) of the declaration which is kept.
+	KeptPath string
+	KeptLine int
+	// The file and the line of the declaration which is dropped.
+	Path string
+	Line int
+}
+
+// Merges the resources files at `paths` into the file at `target`, which is created if it does not exist.
+// The elements of the other files (with their comments) are appended to the `target`, and the files are deleted.
+// The elements declared the same way in more than one file are only kept once; the ones declared differently
+// are returned as conflicts, and the first declaration is kept (the ones of the `target` go first).
+// Returns the number of the dropped duplicates. The changes are recorded in the audit log with the `source`.
+func MergeFiles(target string, paths []string, source string) (int, []MergeConflict, error) {
+	var targetData []byte
+	if data, err := ioutil.ReadFile(target); err == nil {
+		targetData = data
+	} else if !os.IsNotExist(err) {
+		return 0, nil, err
+	} else {
+		targetData = []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n</resources>\n")
+	}
+	targetChunks, err := parseChunks(targetData)
+	if err != nil {
+		return 0, nil, errors.New(fmt.Sprintf("%s: %s", target, err.Error()))
+	}
+	namespaces, rootEnd, err := rootNamespaces(targetData)
+	if err != nil {
+		return 0, nil, errors.New(fmt.Sprintf("%s: %s", target, err.Error()))
+	}
+
+	// the declarations kept so far, keyed by the element and the name
+	type declaration struct {
+		path    string
+		line    int
+		content string
+	}
+	kept := make(map[string]declaration)
+	for _, c := range targetChunks {
+		key := c.element + "/" + c.name
+		if _, ok := kept[key]; !ok {
+			kept[key] = declaration{target, lineAt(targetData, c.elementStart), string(targetData[c.elementStart:c.end])}
+		}
+	}
+	var appended strings.Builder
+	var addedNamespaces []xml.Attr
+	duplicateCount := 0
+	var conflicts []MergeConflict
+	var merged []string
+	for _, path := range paths {
+		if path == target {
+			continue
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return 0, nil, err
+		}
+		chunks, err := parseChunks(data)
+		if err != nil {
+			return 0, nil, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+		}
+		attrs, _, err := rootNamespaces(data)
+		if err != nil {
+			return 0, nil, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+		}
+		for _, attr := range attrs {
+			if !hasNamespace(namespaces, attr.Name.Local) {
+				namespaces = append(namespaces, attr)
+				addedNamespaces = append(addedNamespaces, attr)
+			}
+		}
+		for _, c := range chunks {
+			key := c.element + "/" + c.name
+			content := string(data[c.elementStart:c.end])
+			line := lineAt(data, c.elementStart)
+			if d, ok := kept[key]; ok {
+				if d.content == content {
+					duplicateCount += 1
+				} else {
+					conflicts = append(conflicts, MergeConflict{c.element, c.name, d.path, d.line, path, line})
+				}
+				continue
+			}
+			kept[key] = declaration{path, line, content}
+			appended.WriteString("    ")
+			appended.Write(data[c.start:c.end])
+			appended.WriteString("\n")
+		}
+		merged = append(merged, path)
+	}
+	if len(merged) == 0 {
+		return 0, nil, nil
+	}
+
+	content := string(targetData)
+	idx := strings.LastIndex(content, "</resources>")
+	if idx < 0 {
+		return 0, nil, errors.New(fmt.Sprintf("%s does not have the closing </resources> tag", target))
+	}
+	content = content[:idx] + appended.String() + content[idx:]
+	var declared strings.Builder
+	for _, attr := range addedNamespaces {
+		declared.WriteString(fmt.Sprintf(" xmlns:%s=\"%s\"", attr.Name.Local, attr.Value))
+	}
+	content = content[:rootEnd-1] + declared.String() + content[rootEnd-1:]
+	if err := audit.WriteFile(target, []byte(content), source); err != nil {
+		return 0, nil, err
+	}
+	for _, path := range merged {
+		if err := audit.Remove(path, source); err != nil {
+			return 0, nil, err
+		}
+	}
+	return duplicateCount, conflicts, nil
+}
+
+// Returns the namespace declarations (e.g. xmlns:tools) of the root element of the XML `data`,
+// and the offset of the end of its start tag.
+func rootNamespaces(data []byte) ([]xml.Attr, int64, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, 0, errors.New("The root element is missing")
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			var namespaces []xml.Attr
+			for _, attr := range start.Attr {
+				if attr.Name.Space == "xmlns" {
+					namespaces = append(namespaces, attr)
+				}
+			}
+			return namespaces, decoder.InputOffset(), nil
+		}
+	}
+}
+
+// Returns true if the namespace with the `prefix` is declared in the `namespaces`.
+func hasNamespace(namespaces []xml.Attr, prefix string) bool {
+	for _, attr := range namespaces {
+		if attr.Name.Local == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the line number (starting with 1) of the `offset` in the `data`.
+func lineAt(data []byte, offset int64) int {
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,158 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The configuration qualifiers of the resource directories other than the locale, in the order required by Android
+// (see https://developer.android.com/guide/topics/resources/providing-resources#AlternativeResources).
+var configQualifierRegexes = []*regexp.Regexp{
+	regexp.MustCompile("^mcc[0-9]{3}$"),
+	regexp.MustCompile("^mnc[0-9]{2,3}$"),
+	// the locale goes here
+	regexp.MustCompile("^ld(ltr|rtl)$"),
+	regexp.MustCompile("^sw[0-9]+dp$"),
+	regexp.MustCompile("^w[0-9]+dp$"),
+	regexp.MustCompile("^h[0-9]+dp$"),
+	regexp.MustCompile("^(small|normal|large|xlarge)$"),
+	regexp.MustCompile("^(long|notlong)$"),
+	regexp.MustCompile("^(round|notround)$"),
+	regexp.MustCompile("^(widecg|nowidecg)$"),
+	regexp.MustCompile("^(highdr|lowdr)$"),
+	regexp.MustCompile("^(port|land)$"),
+	regexp.MustCompile("^(car|desk|television|appliance|watch|vrheadset)$"),
+	regexp.MustCompile("^(night|notnight)$"),
+	regexp.MustCompile("^(ldpi|mdpi|tvdpi|hdpi|xhdpi|xxhdpi|xxxhdpi|nodpi|anydpi|[0-9]+dpi)$"),
+	regexp.MustCompile("^(notouch|finger)$"),
+	regexp.MustCompile("^(keysexposed|keyshidden|keyssoft)$"),
+	regexp.MustCompile("^(nokeys|qwerty|12key)$"),
+	regexp.MustCompile("^(navexposed|navhidden)$"),
+	regexp.MustCompile("^(nonav|dpad|trackball|wheel)$"),
+	regexp.MustCompile("^v[0-9]+$"),
+}
+
+// The number of the configuration qualifiers that precede the locale (MCC and MNC).
+const qualifiersBeforeLocale = 2
+
+var languageQualifierRegex *regexp.Regexp = regexp.MustCompile("^[a-z]{2,3}$")
+var regionQualifierRegex *regexp.Regexp = regexp.MustCompile("^r([A-Z]{2}|[0-9]{3})$")
+var bcp47QualifierRegex *regexp.Regexp = regexp.MustCompile("^b\\+[a-z]{2,3}(\\+[a-zA-Z0-9]{2,8})*$")
+
+// The qualifiers of a resource directory, e.g. "values-de-rAT-night-v21".
+type Qualifiers struct {
+	// The locale qualifier (e.g. "de-rAT" or "b+sr+Latn"), or an empty string.
+	Locale string
+	// The configuration qualifiers other than the locale (e.g. "night" and "v21").
+	Config []string
+}
+
+// Returns the name of the values directory with the qualifiers, e.g. "values-mcc310-de-night".
+func (q *Qualifiers) Dir() string {
+	parts := []string{"values"}
+	locale := q.Locale
+	for _, c := range q.Config {
+		if len(locale) > 0 && configQualifierIndex(c) > qualifiersBeforeLocale {
+			parts = append(parts, locale)
+			locale = ""
+		}
+		parts = append(parts, c)
+	}
+	if len(locale) > 0 {
+		parts = append(parts, locale)
+	}
+	return strings.Join(parts, "-")
+}
+
+// Parses the qualifiers of the resource directory named `dir` (e.g. "values-de-rAT-night").
+// Returns an error describing the malformed, unknown or misordered qualifiers, with a correction if one is known
+// (e.g. "en-rUS" for "en-US").
+func ParseQualifiers(dir string) (*Qualifiers, error) {
+	q := &Qualifiers{}
+	parts := strings.Split(dir, "-")
+	if len(parts) < 2 {
+		return q, nil
+	}
+	parts = parts[1:]
+	lastIndex := -1
+	for i := 0; i < len(parts); i++ {
+		part, next := parts[i], ""
+		if i+1 < len(parts) {
+			next = parts[i+1]
+		}
+		index := configQualifierIndex(part)
+		if index < 0 {
+			if len(q.Locale) > 0 {
+				return q, errors.New(fmt.Sprintf("'%s' is not a known qualifier", part))
+			}
+			if lastIndex >= qualifiersBeforeLocale {
+				return q, errors.New(fmt.Sprintf("'%s' is not a known qualifier, or a locale qualifier out of order (the locale must precede the other qualifiers except MCC and MNC)", part))
+			}
+			if bcp47QualifierRegex.MatchString(part) {
+				q.Locale = part
+			} else if languageQualifierRegex.MatchString(part) {
+				q.Locale = part
+				if regionQualifierRegex.MatchString(next) {
+					q.Locale += "-" + next
+					i++
+				} else if len(next) > 0 && configQualifierIndex(next) < 0 {
+					return q, errors.New(fmt.Sprintf("'%s' is not a valid region qualifier%s", next, localeHint(part, next)))
+				}
+			} else {
+				hintNext := next
+				if configQualifierIndex(next) >= 0 {
+					hintNext = ""
+				}
+				return q, errors.New(fmt.Sprintf("'%s' is not a valid locale qualifier%s", part, localeHint(part, hintNext)))
+			}
+			lastIndex = qualifiersBeforeLocale
+			continue
+		}
+		if index <= lastIndex {
+			return q, errors.New(fmt.Sprintf("The qualifier '%s' is out of order (see the order of the qualifiers defined by Android)", part))
+		}
+		lastIndex = index
+		q.Config = append(q.Config, part)
+	}
+	return q, nil
+}
+
+// Returns the index of the configuration qualifier `part` in the order defined by Android,
+// or -1 if it is not a configuration qualifier.
+func configQualifierIndex(part string) int {
+	for i, regex := range configQualifierRegexes {
+		if regex.MatchString(part) {
+			if i >= qualifiersBeforeLocale {
+				return i + 1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns a hint with the valid locale qualifier for the malformed `part`, followed by the `next` qualifier
+// (e.g. " (did you mean 'en-rUS'?)" for "en-US" or "en_US"), or an empty string.
+func localeHint(part, next string) string {
+	language, region := part, next
+	if subtags := strings.SplitN(part, "_", 2); len(subtags) == 2 {
+		language, region = subtags[0], subtags[1]
+	}
+	language = strings.ToLower(language)
+	if !languageQualifierRegex.MatchString(language) {
+		return ""
+	}
+	suggestion := language
+	if len(region) == 4 {
+		// a script, e.g. "sr-Latn"
+		suggestion = fmt.Sprintf("b+%s+%s", language, region)
+	} else if region = "r" + strings.ToUpper(region); regionQualifierRegex.MatchString(region) {
+		suggestion += "-" + region
+	}
+	if suggestion == part {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+}
@@ -0,0 +1,689 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The `Value` of the string, plural item and string-array item elements is the text content
+// of the element (including nested elements like `<xliff:g>`, and the content of CDATA sections verbatim),
+// filled by `ParseFile`. The `RawValue` is the inner XML of the element. The `CompiledValue` is the `Value`
+// as aapt compiles it (see `CompileValue`), e.g. "a  b" for `"a  b"`.
+// The `Ignore` is the value of the tools:ignore attribute (see `IsIgnored`).
+// The `Line` and `Column` are the position (starting with 1) of the element's start tag in the file.
+type String struct {
+	Name          string `xml:"name,attr"`
+	Translatable  string `xml:"translatable,attr"`
+	Formatted     string `xml:"formatted,attr"`
+	Ignore        string `xml:"ignore,attr"`
+	Value         string `xml:"-"`
+	CompiledValue string `xml:"-"`
+	RawValue      string `xml:",innerxml"`
+	Line          int    `xml:"-"`
+	Column        int    `xml:"-"`
+}
+
+type PluralItem struct {
+	Quantity      string `xml:"quantity,attr"`
+	Value         string `xml:"-"`
+	CompiledValue string `xml:"-"`
+	RawValue      string `xml:",innerxml"`
+}
+
+type Plural struct {
+	Name         string       `xml:"name,attr"`
+	Translatable string       `xml:"translatable,attr"`
+	Ignore       string       `xml:"ignore,attr"`
+	Items        []PluralItem `xml:"item"`
+	Line         int          `xml:"-"`
+	Column       int          `xml:"-"`
+}
+
+type StringArrayItem struct {
+	Value         string `xml:"-"`
+	CompiledValue string `xml:"-"`
+	RawValue      string `xml:",innerxml"`
+}
+
+type StringArray struct {
+	Name         string            `xml:"name,attr"`
+	Translatable string            `xml:"translatable,attr"`
+	Ignore       string            `xml:"ignore,attr"`
+	Items        []StringArrayItem `xml:"item"`
+	Line         int               `xml:"-"`
+	Column       int               `xml:"-"`
+}
+
+// The string resources declared in a single XML file.
+type Resources struct {
+	// The tools:locale attribute of the <resources> element (e.g. "es" or "pt-BR"), i.e. the language of the file.
+	ToolsLocale  string        `xml:"locale,attr"`
+	Strings      []String      `xml:"string"`
+	Plurals      []Plural      `xml:"plurals"`
+	StringArrays []StringArray `xml:"string-array"`
+	// The rules suppressed with the validator:ignore comments, keyed by the resource name.
+	ignoreComments map[string][]string
+	// The positions of the resources keyed by their names; may be nil or outdated, see `index`.
+	index *index
+	// The maximal lengths declared with the maxLength=N comments, keyed by the resource name.
+	maxLengths map[string]int
+}
+
+// Returns the name of the values directory for the `locale` (e.g. "values-de", or "values" for an empty locale).
+func ValuesDir(locale string) string {
+	if len(locale) > 0 {
+		return fmt.Sprintf("values-%s", locale)
+	}
+	return "values"
+}
+
+// Extracts the locale (e.g. "pt-rBR") from the path of a strings file (e.g. "values-pt-rBR/strings.xml").
+// Returns an empty string for the default "values" directory.
+func LocaleFromPath(path string) string {
+	dir := filepath.Base(filepath.Dir(path))
+	return strings.TrimPrefix(strings.TrimPrefix(dir, "values"), "-")
+}
+
+// Matches the locale qualifiers of the values directories, e.g. "de", "pt-rBR" or "b+sr+Latn".
+var localeQualifierRegex *regexp.Regexp = regexp.MustCompile("^([a-z]{2,3}(-r[A-Z]{2})?|b\\+[a-zA-Z0-9+]+)$")
+
+// Returns true if the `locale` (e.g. as returned by `LocaleFromPath`) is a locale qualifier,
+// not another qualifier (like "night" or "v21").
+func IsLocaleQualifier(locale string) bool {
+	return localeQualifierRegex.MatchString(locale)
+}
+
+// Returns true if the `locale` is one of the Android pseudo-locales ("en-rXA" or "ar-rXB"),
+// which are generated from the base strings instead of being translated.
+func IsPseudoLocale(locale string) bool {
+	return locale == "en-rXA" || locale == "ar-rXB"
+}
+
+// Converts the Android locale (e.g. "pt-rBR" or "b+sr+Latn") to the BCP 47 language tag (e.g. "pt-BR" or "sr-Latn").
+func LanguageTag(locale string) string {
+	if strings.HasPrefix(locale, "b+") {
+		return strings.Replace(strings.TrimPrefix(locale, "b+"), "+", "-", -1)
+	}
+	return strings.Replace(locale, "-r", "-", 1)
+}
+
+// The UI mode qualifiers of the form-factor resource overlays (e.g. "values-watch" or "values-de-television").
+var formFactorQualifiers = map[string]bool{
+	"car":        true,
+	"desk":       true,
+	"television": true,
+	"appliance":  true,
+	"watch":      true,
+	"vrheadset":  true,
+}
+
+// Splits the qualifiers of the values directory of the file at `path` (e.g. "values-de-watch/strings.xml")
+// into the locale (e.g. "de") and the form factor (e.g. "watch").
+// The form factor is empty if the directory does not have a UI mode qualifier, and the locale is empty if it has no other qualifiers.
+func FormFactorFromPath(path string) (locale, formFactor string) {
+	qualifiers := LocaleFromPath(path)
+	parts := strings.Split(qualifiers, "-")
+	for i, part := range parts {
+		if formFactorQualifiers[part] {
+			return strings.Join(parts[:i], "-"), part
+		}
+	}
+	return qualifiers, ""
+}
+
+// Converts the BCP 47 language tag (e.g. "pt-BR" as used by tools:locale) to the Android locale (e.g. "pt-rBR").
+// The tags with a script or a variant (e.g. "sr-Latn" or "zh-Hans-CN") are converted to the "b+" locales (e.g. "b+sr+Latn").
+func LocaleFromLanguageTag(tag string) string {
+	parts := strings.Split(tag, "-")
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	if len(parts) == 2 && regionQualifierRegex.MatchString("r"+strings.ToUpper(parts[1])) {
+		return fmt.Sprintf("%s-r%s", parts[0], strings.ToUpper(parts[1]))
+	}
+	return "b+" + strings.Join(parts, "+")
+}
+
+// Returns true if the Android locales `a` and `b` are the same, e.g. "pt-rBR" and "b+pt+BR".
+func SameLocale(a, b string) bool {
+	return strings.EqualFold(LanguageTag(a), LanguageTag(b))
+}
+
+// Returns the locale of the file at `path` with the resources `r`: the locale qualifier of the directory,
+// or the tools:locale attribute if the directory does not have a locale qualifier (e.g. "values" or "values-night").
+func (r *Resources) ResolveLocale(path string) string {
+	if locale := LocaleFromPath(path); IsLocaleQualifier(locale) || len(r.ToolsLocale) == 0 {
+		return locale
+	}
+	return LocaleFromLanguageTag(r.ToolsLocale)
+}
+
+// Extracts the short path for a string file (e.g. "values-en/strings.xml")
+// based on the `resDir` path and the `stringsFilePath`.
+// If extraction fails, it returns `stringsFilePath`.
+func ShortPath(resDir, stringsFilePath string) string {
+	p, err := filepath.Rel(resDir, stringsFilePath)
+	if err != nil {
+		p = stringsFilePath
+	}
+	return p
+}
+
+// Constructs the file path from `resDir`, `localeName` and `stringsFilename`,
+// and returns parsed resources or an error.
+func Parse(resDir, localeName, stringsFilename string) (*Resources, error) {
+	var path string = filepath.Join(resDir, ValuesDir(localeName), stringsFilename)
+	return ParseFile(path)
+}
+
+// Reads a file at a `path` and returns parsed resources object, or an error.
+// If the filename is a file set (see `IsFileSet`), the resources of all its files in the directory are merged.
+func ParseFile(path string) (*Resources, error) {
+	if IsFileSet(filepath.Base(path)) {
+		paths, err := ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+		var all []*Resources
+		for _, p := range paths {
+			r, err := ParseFile(p)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, r)
+		}
+		return merge(all), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	resources, err := decode(file)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+	}
+	return resources, nil
+}
+
+// Parses the XML `data` and returns the resources object, or an error.
+func ParseData(data []byte) (*Resources, error) {
+	return decode(bytes.NewReader(data))
+}
+
+// Reads the resources from the XML stream `r` one element at a time, so that only the resources
+// (and not the whole file) are kept in the memory, and records the position of every element.
+func decode(r io.Reader) (*Resources, error) {
+	resources := &Resources{}
+	comments := make(map[string]string)
+	decoder := xml.NewDecoder(r)
+	depth := 0
+	lastComment := ""
+	for {
+		line, column := decoder.InputPos()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+			if depth == 1 {
+				lastComment = strings.TrimSpace(string(t))
+			}
+		case xml.StartElement:
+			if depth == 0 {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "locale" {
+						resources.ToolsLocale = attr.Value
+					}
+				}
+				depth += 1
+				continue
+			}
+			name, err := decodeElement(decoder, &t, resources, line, column)
+			if err != nil {
+				return nil, err
+			}
+			if len(name) > 0 && len(lastComment) > 0 {
+				comments[name] = lastComment
+			}
+			lastComment = ""
+		case xml.EndElement:
+			depth -= 1
+		case xml.CharData:
+			// A blank line between the comment and the element detaches the comment.
+			if strings.Count(string(t), "\n") > 1 {
+				lastComment = ""
+			}
+		}
+	}
+	if err := resolveValues(resources); err != nil {
+		return nil, err
+	}
+	resources.ignoreComments = parseIgnoreComments(comments)
+	resources.maxLengths = parseMaxLengthComments(comments)
+	resources.reindex()
+	return resources, nil
+}
+
+// Decodes the child element `start` of the <resources> element into the `resources`, with the position
+// of its start tag at the `line` and `column`. Returns the name of the resource, or an empty string
+// for the elements other than <string>, <plurals> and <string-array>, which are skipped.
+func decodeElement(decoder *xml.Decoder, start *xml.StartElement, resources *Resources, line, column int) (string, error) {
+	switch start.Name.Local {
+	case "string":
+		el := String{Line: line, Column: column}
+		if err := decoder.DecodeElement(&el, start); err != nil {
+			return "", err
+		}
+		resources.Strings = append(resources.Strings, el)
+		return el.Name, nil
+	case "plurals":
+		el := Plural{Line: line, Column: column}
+		if err := decoder.DecodeElement(&el, start); err != nil {
+			return "", err
+		}
+		resources.Plurals = append(resources.Plurals, el)
+		return el.Name, nil
+	case "string-array":
+		el := StringArray{Line: line, Column: column}
+		if err := decoder.DecodeElement(&el, start); err != nil {
+			return "", err
+		}
+		resources.StringArrays = append(resources.StringArrays, el)
+		return el.Name, nil
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "name" {
+			return attr.Value, decoder.Skip()
+		}
+	}
+	return "", decoder.Skip()
+}
+
+// Returns the comments that directly precede the resource elements in the XML `data`,
+// keyed by the resource name. Such comments usually give the translators a context.
+func ParseComments(data []byte) (map[string]string, error) {
+	comments := make(map[string]string)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	lastComment := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return comments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+			if depth == 1 {
+				lastComment = strings.TrimSpace(string(t))
+			}
+		case xml.StartElement:
+			if depth == 1 && len(lastComment) > 0 {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "name" {
+						comments[attr.Value] = lastComment
+					}
+				}
+			}
+			lastComment = ""
+			depth += 1
+		case xml.EndElement:
+			depth -= 1
+		case xml.CharData:
+			// A blank line between the comment and the element detaches the comment.
+			if strings.Count(string(t), "\n") > 1 {
+				lastComment = ""
+			}
+		}
+	}
+}
+
+// Returns the text of every resource keyed by its name.
+// The plural items are joined as "quantity: text" and the string-array items as "text | text".
+func (r *Resources) Texts() map[string]string {
+	texts := make(map[string]string)
+	for _, el := range r.Strings {
+		texts[el.Name] = el.Value
+	}
+	for _, el := range r.Plurals {
+		var items []string
+		for _, item := range el.Items {
+			items = append(items, fmt.Sprintf("%s: %s", item.Quantity, item.Value))
+		}
+		texts[el.Name] = strings.Join(items, "; ")
+	}
+	for _, el := range r.StringArrays {
+		var items []string
+		for _, item := range el.Items {
+			items = append(items, item.Value)
+		}
+		texts[el.Name] = strings.Join(items, " | ")
+	}
+	return texts
+}
+
+// Generates the file paths for other string resource files.
+// `resDir` is the path to the Android's "res" directory.
+// `exceptForLocale` is the locale of the file path, that will not be included in the returned paths.
+// `stringsFilename` is the name of the XML file that contains the string resources (e.g. "strings.xml").
+func OtherLocalePaths(resDir, exceptForLocale, stringsFilename string) ([]string, error) {
+	if IsFileSet(stringsFilename) {
+		return otherLocaleFileSetPaths(resDir, exceptForLocale, stringsFilename)
+	}
+	patt := filepath.Join(resDir, "values-*", stringsFilename)
+	paths, err := filepath.Glob(patt)
+	if err != nil {
+		return nil, err
+	}
+	patt2 := filepath.Join(resDir, "values", stringsFilename)
+	paths2, err := filepath.Glob(patt2)
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, paths2...)
+	exceptForPath := filepath.Join(resDir, ValuesDir(exceptForLocale), stringsFilename)
+
+	idx := -1
+	for i, p := range paths {
+		if p == exceptForPath {
+			idx = i
+			break
+		}
+	}
+
+	if idx >= 0 {
+		paths = append(paths[:idx], paths[idx+1:]...)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Returns the paths of the file set `stringsFilename` (see `IsFileSet`) in the values directories of the `resDir`
+// that have any of its files, except for the `exceptForLocale`; e.g. "res/values-de/strings.xml,plurals.xml".
+func otherLocaleFileSetPaths(resDir, exceptForLocale, stringsFilename string) ([]string, error) {
+	dirs, err := filepath.Glob(filepath.Join(resDir, "values*"))
+	if err != nil {
+		return nil, err
+	}
+	exceptForDir := filepath.Join(resDir, ValuesDir(exceptForLocale))
+	var paths []string
+	for _, dir := range dirs {
+		name := filepath.Base(dir)
+		if dir == exceptForDir || (name != "values" && !strings.HasPrefix(name, "values-")) {
+			continue
+		}
+		path := filepath.Join(dir, stringsFilename)
+		if files, err := ExpandPath(path); err != nil {
+			return nil, err
+		} else if len(files) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Returns false if the string is marked with translatable="false".
+func (s *String) IsTranslatable() bool {
+	return s.Translatable != "false"
+}
+
+// Returns false if the string is marked with formatted="false", i.e. it is not a format string
+// and may contain literal "%" characters.
+func (s *String) IsFormatted() bool {
+	return s.Formatted != "false"
+}
+
+// Matches a reference to a string resource of the app, e.g. "@string/app_name".
+var stringReferenceRegex *regexp.Regexp = regexp.MustCompile("^@\\+?string/([A-Za-z0-9_.]+)$")
+
+// Returns the name of the string referenced by the value (e.g. "app_name" for "@string/app_name"),
+// or an empty string if the value is not a reference. The references to the framework strings (e.g. "@android:string/ok") are not resolved.
+func (s *String) Reference() string {
+	if match := stringReferenceRegex.FindStringSubmatch(strings.TrimSpace(s.Value)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Returns the name of the string referenced by the item value (e.g. "app_name" for "@string/app_name"),
+// or an empty string if the value is not a reference (see `String.Reference`).
+func (i *StringArrayItem) Reference() string {
+	if match := stringReferenceRegex.FindStringSubmatch(strings.TrimSpace(i.Value)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Returns false if the plurals element is marked with translatable="false".
+func (p *Plural) IsTranslatable() bool {
+	return p.Translatable != "false"
+}
+
+// Returns false if the string-array is marked with translatable="false".
+func (a *StringArray) IsTranslatable() bool {
+	return a.Translatable != "false"
+}
+
+// Returns false if the resource named `name` is marked with translatable="false".
+func (r *Resources) IsTranslatable(name string) bool {
+	if el := r.FindString(name); el != nil {
+		return el.IsTranslatable()
+	}
+	if el := r.FindPlural(name); el != nil {
+		return el.IsTranslatable()
+	}
+	if el := r.FindStringArray(name); el != nil {
+		return el.IsTranslatable()
+	}
+	return true
+}
+
+func (r *Resources) FindString(name string) *String {
+	if i, found, ok := r.indexOfString(name); ok {
+		if !found {
+			return nil
+		}
+		el := r.Strings[i]
+		return &el
+	}
+	for _, el := range r.Strings {
+		if el.Name == name {
+			return &el
+		}
+	}
+	return nil
+}
+
+func (r *Resources) FindStringArray(name string) *StringArray {
+	if i, found, ok := r.indexOfStringArray(name); ok {
+		if !found {
+			return nil
+		}
+		el := r.StringArrays[i]
+		return &el
+	}
+	for _, el := range r.StringArrays {
+		if el.Name == name {
+			return &el
+		}
+	}
+	return nil
+}
+
+func (r *Resources) FindPlural(name string) *Plural {
+	if i, found, ok := r.indexOfPlural(name); ok {
+		if !found {
+			return nil
+		}
+		el := r.Plurals[i]
+		return &el
+	}
+	for _, el := range r.Plurals {
+		if el.Name == name {
+			return &el
+		}
+	}
+	return nil
+}
+
+func (p *Plural) FindItem(quantity string) *PluralItem {
+	for _, item := range p.Items {
+		if item.Quantity == quantity {
+			return &item
+		}
+	}
+	return nil
+}
+
+// Decodes the raw (inner XML) value of a string and calls `handleToken` for each token.
+func DecodeRawValue(rawValue string, handleToken func(token xml.Token)) error {
+	decoder := xml.NewDecoder(strings.NewReader("<value>" + rawValue + "</value>"))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		handleToken(token)
+	}
+}
+
+// Returns the text content of the raw (inner XML) value, including the text inside nested elements
+// like `<b>` or `<xliff:g>`, and the content of CDATA sections.
+func ExtractText(rawValue string) (string, error) {
+	var text strings.Builder
+	err := DecodeRawValue(rawValue, func(token xml.Token) {
+		if charData, ok := token.(xml.CharData); ok {
+			text.Write(charData)
+		}
+	})
+	return text.String(), err
+}
+
+// Fills the `Value` and the `CompiledValue` of every string, plural item and string-array item from its raw value.
+func resolveValues(resources *Resources) error {
+	var err error
+	for i := range resources.Strings {
+		el := &resources.Strings[i]
+		if el.Value, err = ExtractText(el.RawValue); err != nil {
+			return err
+		}
+		el.CompiledValue = CompileValue(el.Value)
+	}
+	for i := range resources.Plurals {
+		for j := range resources.Plurals[i].Items {
+			item := &resources.Plurals[i].Items[j]
+			if item.Value, err = ExtractText(item.RawValue); err != nil {
+				return err
+			}
+			item.CompiledValue = CompileValue(item.Value)
+		}
+	}
+	for i := range resources.StringArrays {
+		for j := range resources.StringArrays[i].Items {
+			item := &resources.StringArrays[i].Items[j]
+			if item.Value, err = ExtractText(item.RawValue); err != nil {
+				return err
+			}
+			item.CompiledValue = CompileValue(item.Value)
+		}
+	}
+	return nil
+}
+
+// Returns the text `value` with the quoting of aapt applied: the unescaped double quotes are removed and the whitespace
+// between them is kept as it is, while outside of them every run of whitespace becomes a single space,
+// and the whitespace at the start and the end is removed. The escape sequences (e.g. \n or \") are kept.
+func CompileValue(value string) string {
+	var compiled strings.Builder
+	escaped := false
+	inQuotes := false
+	pendingSpace := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '"':
+			if pendingSpace {
+				compiled.WriteRune(' ')
+				pendingSpace = false
+			}
+			inQuotes = !inQuotes
+			continue
+		case !inQuotes && (r == ' ' || r == '\n' || r == '\t' || r == '\r'):
+			pendingSpace = compiled.Len() > 0
+			continue
+		case r == '\\':
+			escaped = true
+		}
+		if pendingSpace {
+			compiled.WriteRune(' ')
+			pendingSpace = false
+		}
+		compiled.WriteRune(r)
+	}
+	return compiled.String()
+}
+
+// A resource element declared in a strings file.
+type Declaration struct {
+	// The name of the element, e.g. "string", "plurals" or "string-array".
+	Element string
+	Name    string
+	// The line number (starting with 1) of the element's start tag.
+	Line int
+}
+
+// Returns the resource elements declared in the XML `data`, in the order of declaration.
+func ParseDeclarations(data []byte) ([]Declaration, error) {
+	var declarations []Declaration
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return declarations, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 1 {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "name" {
+						line := bytes.Count(data[:offset], []byte("\n")) + 1
+						declarations = append(declarations, Declaration{t.Name.Local, attr.Value, line})
+					}
+				}
+			}
+			depth += 1
+		case xml.EndElement:
+			depth -= 1
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOtherLocalePathsAreSorted(t *testing.T) {
+	tests := []struct {
+		name            string
+		stringsFilename string
+		files           []string
+		want            []string
+	}{
+		{
+			"single file",
+			"strings.xml",
+			[]string{"values-pl/strings.xml", "values/strings.xml", "values-de/strings.xml", "values-b+sr+Latn/strings.xml", "values-en/strings.xml"},
+			[]string{"values-b+sr+Latn/strings.xml", "values-de/strings.xml", "values-pl/strings.xml", "values/strings.xml"},
+		},
+		{
+			"file set",
+			"strings*.xml",
+			[]string{"values-pl/strings_b.xml", "values-pl/strings_a.xml", "values-de/strings.xml", "values-en/strings.xml"},
+			[]string{"values-de/strings*.xml", "values-pl/strings*.xml"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resDir := t.TempDir()
+			// created in the reverse order, so that the order of the directory entries does not matter
+			for i := len(test.files) - 1; i >= 0; i-- {
+				path := filepath.Join(resDir, test.files[i])
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("<resources/>"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			paths, err := OtherLocalePaths(resDir, "en", test.stringsFilename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, path := range paths {
+				got = append(got, ShortPath(resDir, path))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompileValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Hello", "Hello"},
+		{"collapsed whitespace", "  Hello \n\t world  ", "Hello world"},
+		{"quoted", `"  Hello  world "`, "  Hello  world "},
+		{"partly quoted", `Hello " world "  again`, "Hello  world  again"},
+		{"escaped quote", `Say \"hi\"  now`, `Say \"hi\" now`},
+		{"escaped whitespace", `Hello\n  \t world`, `Hello\n \t world`},
+		{"escaped backslash before quote", `a\\" b "`, `a\\ b `},
+		{"empty quotes", `""`, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CompileValue(test.value); got != test.want {
+				t.Errorf("CompileValue(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFileCompilesValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strings.xml")
+	content := `<resources>
+    <string name="quoted">"  Hello  "</string>
+    <string name="escaped">Say \"hi\"   now</string>
+    <string name="cdata"><![CDATA[ "<b>%1$s</b>  " ]]></string>
+    <plurals name="items">
+        <item quantity="other">"%d  items"</item>
+    </plurals>
+    <string-array name="names">
+        <item>  Alice   Smith </item>
+    </string-array>
+</resources>
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"quoted", res.FindString("quoted").CompiledValue, "  Hello  "},
+		{"escaped", res.FindString("escaped").CompiledValue, `Say \"hi\" now`},
+		{"cdata", res.FindString("cdata").CompiledValue, "<b>%1$s</b>  "},
+		{"plural item", res.Plurals[0].Items[0].CompiledValue, "%d  items"},
+		{"string-array item", res.StringArrays[0].Items[0].CompiledValue, "Alice Smith"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("got the compiled value %q of %s, want %q", test.got, test.name, test.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// A resource element in the file data, together with the comments preceding it.
+type chunk struct {
+	element string
+	name    string
+	// the offsets of the first comment (or the element, if there are no comments), the element, and the end of the element
+	start, elementStart, end int64
+}
+
+// Returns true if the resource `a` goes before the resource `b` in the alphabetical order of the names, ignoring the case.
+func LessName(a, b string) bool {
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// Returns an order of the resources (see `SortFile`) in which the resources with the `names` go in the order of the `names`,
+// followed by the other resources.
+func ByNames(names []string) func(a, b string) bool {
+	positions := make(map[string]int)
+	for i, name := range names {
+		if _, ok := positions[name]; !ok {
+			positions[name] = i
+		}
+	}
+	position := func(name string) int {
+		if i, ok := positions[name]; ok {
+			return i
+		}
+		return len(names)
+	}
+	return func(a, b string) bool {
+		return position(a) < position(b)
+	}
+}
+
+// Sorts the resource elements in the file at `path` with the `less` function of their names (e.g. `LessName`).
+// The comments preceding an element are moved with it, and the rest of the file is left as it is.
+// The elements with the same position in the order keep their relative order.
+// Returns true if the file was changed; the change is recorded in the audit log with the `source`.
+func SortFile(path string, less func(a, b string) bool, source string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	sorted, err := sortElements(data, less)
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+	}
+	if bytes.Equal(data, sorted) {
+		return false, nil
+	}
+	return true, audit.WriteFile(path, sorted, source)
+}
+
+// Returns the XML `data` with the elements sorted by the `less` function of their names.
+// The whitespace between the elements stays in place, so that the layout of the file does not change.
+func sortElements(data []byte, less func(a, b string) bool) ([]byte, error) {
+	chunks, err := parseChunks(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) < 2 {
+		return data, nil
+	}
+	sorted := append([]chunk{}, chunks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i].name, sorted[j].name)
+	})
+	var result bytes.Buffer
+	result.Write(data[:chunks[0].start])
+	for i, c := range sorted {
+		result.Write(data[c.start:c.end])
+		if i+1 < len(chunks) {
+			result.Write(data[chunks[i].end:chunks[i+1].start])
+		}
+	}
+	result.Write(data[chunks[len(chunks)-1].end:])
+	return result.Bytes(), nil
+}
+
+// Returns the elements declared in the root element of the XML `data`, each with the comments preceding it.
+func parseChunks(data []byte) ([]chunk, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	// the offset of the first comment preceding the next element; -1 if there is none
+	commentStart := int64(-1)
+	var chunks []chunk
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+			if depth == 1 && commentStart < 0 {
+				commentStart = offset
+			}
+		case xml.EndElement:
+			depth -= 1
+		case xml.StartElement:
+			if depth == 0 {
+				depth += 1
+				continue
+			}
+			start := offset
+			if commentStart >= 0 {
+				start = commentStart
+			}
+			commentStart = -1
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, chunk{t.Name.Local, attrValue(t, "name"), start, offset, decoder.InputOffset()})
+		}
+	}
+}
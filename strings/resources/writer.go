@@ -0,0 +1,246 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Escapes the text so that it can be used as a value of a `<string>` element,
+// and aapt compiles it back to the same text.
+func EscapeValue(text string) string {
+	var escaped strings.Builder
+	for i, r := range text {
+		switch r {
+		case '\\':
+			escaped.WriteString("\\\\")
+		case '\'':
+			escaped.WriteString("\\'")
+		case '"':
+			escaped.WriteString("\\\"")
+		case '\n':
+			escaped.WriteString("\\n")
+		case '\t':
+			escaped.WriteString("\\t")
+		case '&':
+			escaped.WriteString("&amp;")
+		case '<':
+			escaped.WriteString("&lt;")
+		case '>':
+			escaped.WriteString("&gt;")
+		case '@', '?':
+			if i == 0 {
+				escaped.WriteRune('\\')
+			}
+			escaped.WriteRune(r)
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	value := escaped.String()
+	// aapt collapses and trims the whitespace, unless the value is quoted
+	if strings.TrimSpace(value) != value || strings.Contains(value, "  ") {
+		value = "\"" + value + "\""
+	}
+	return value
+}
+
+// Resolves the Android escape sequences and quoting in the text content of a `<string>` element,
+// returning the text the way aapt compiles it.
+func UnescapeValue(value string) string {
+	var text strings.Builder
+	escaped := false
+	inQuotes := false
+	lastWasSpace := false
+	runes := []rune(strings.TrimSpace(value))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if escaped {
+			escaped = false
+			lastWasSpace = false
+			switch r {
+			case 'n':
+				text.WriteRune('\n')
+			case 't':
+				text.WriteRune('\t')
+			case 'u':
+				if i+4 < len(runes) {
+					var code rune
+					if _, err := fmt.Sscanf(string(runes[i+1:i+5]), "%04x", &code); err == nil {
+						text.WriteRune(code)
+						i += 4
+						continue
+					}
+				}
+				text.WriteRune(r)
+			default:
+				text.WriteRune(r)
+			}
+			continue
+		}
+		switch {
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ' ' || r == '\n' || r == '\t'):
+			if !lastWasSpace {
+				text.WriteRune(' ')
+			}
+			lastWasSpace = true
+			continue
+		default:
+			text.WriteRune(r)
+		}
+		lastWasSpace = false
+	}
+	return text.String()
+}
+
+// Appends the `<string>` elements to the resources file at `path`, just before the closing `</resources>` tag.
+// The `RawValue` of the `strs` is written as is, so it is expected to be escaped (see `EscapeValue`).
+// The change is recorded in the audit log with the `source` of the strings.
+func AppendStrings(path string, strs []String, source string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	idx := strings.LastIndex(content, "</resources>")
+	if idx < 0 {
+		return errors.New(fmt.Sprintf("%s does not have the closing </resources> tag", path))
+	}
+
+	var appended strings.Builder
+	for _, s := range strs {
+		appended.WriteString(fmt.Sprintf("    <string name=\"%s\">%s</string>\n", s.Name, s.RawValue))
+	}
+	content = content[:idx] + appended.String() + content[idx:]
+	return audit.WriteFile(path, []byte(content), source)
+}
+
+// Removes the strings, plurals and string-arrays with the `names` from the resources file at `path`,
+// together with the comments directly preceding them (see `ParseComments`), leaving the rest of the file as it is.
+// Returns the number of the removed elements; the file is not written if none was removed.
+// The change is recorded in the audit log with the `source`.
+func RemoveResources(path string, names map[string]bool, source string) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	removed, count, err := removeElements(data, names)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, audit.WriteFile(path, removed, source)
+}
+
+// Returns the XML `data` without the resource elements with the `names` and their comments,
+// and the number of the removed elements.
+func removeElements(data []byte, names map[string]bool) ([]byte, int, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	// the offset of the comment directly preceding the next element; -1 if there is none
+	commentStart := int64(-1)
+	// the parts of the `data` to remove, as pairs of the start and end offsets
+	var removed [][2]int64
+	count := 0
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+			if depth == 1 {
+				commentStart = offset
+			}
+		case xml.CharData:
+			if strings.Count(string(t), "\n") > 1 {
+				commentStart = -1
+			}
+		case xml.EndElement:
+			depth -= 1
+		case xml.StartElement:
+			if depth == 0 {
+				depth += 1
+				continue
+			}
+			start := offset
+			if commentStart >= 0 {
+				start = commentStart
+			}
+			commentStart = -1
+			if err := decoder.Skip(); err != nil {
+				return nil, 0, err
+			}
+			if !isResourceElement(t.Name.Local) || !names[attrValue(t, "name")] {
+				continue
+			}
+			end := decoder.InputOffset()
+			count += 1
+			// the elements on the same line are removed together, so that no blank line is left
+			if n := len(removed); n > 0 && len(strings.Trim(string(data[removed[n-1][1]:start]), " \t")) == 0 {
+				removed[n-1][1] = end
+			} else {
+				removed = append(removed, [2]int64{start, end})
+			}
+		}
+	}
+	var kept bytes.Buffer
+	var keptUntil int64
+	for _, r := range removed {
+		start, end := lineBounds(data, r[0], r[1])
+		kept.Write(data[keptUntil:start])
+		keptUntil = end
+	}
+	kept.Write(data[keptUntil:])
+	return kept.Bytes(), count, nil
+}
+
+// Returns true if the element named `name` is a string, plurals or string-array.
+func isResourceElement(name string) bool {
+	return name == "string" || name == "plurals" || name == "string-array"
+}
+
+// Returns the value of the attribute `name` of the element, or an empty string if it is not set.
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// Extends the `start` and the `end` offsets of a part of the `data` to the whole lines,
+// if there is only whitespace between them and the line boundaries.
+func lineBounds(data []byte, start, end int64) (int64, int64) {
+	lineStart := start
+	for lineStart > 0 && (data[lineStart-1] == ' ' || data[lineStart-1] == '\t') {
+		lineStart -= 1
+	}
+	lineEnd := end
+	for lineEnd < int64(len(data)) && (data[lineEnd] == ' ' || data[lineEnd] == '\t' || data[lineEnd] == '\r') {
+		lineEnd += 1
+	}
+	if (lineStart == 0 || data[lineStart-1] == '\n') && (lineEnd == int64(len(data)) || data[lineEnd] == '\n') {
+		if lineEnd < int64(len(data)) {
+			lineEnd += 1
+		}
+		return lineStart, lineEnd
+	}
+	return start, end
+}
@@ -0,0 +1,88 @@
+package server
+
+// The dashboard page, which renders the status returned by /api/status.
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Strings dashboard</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.error { color: #b00020; }
+.warning { color: #b26a00; }
+.info { color: #555; }
+.bar { background: #eee; width: 120px; height: 10px; display: inline-block; }
+.bar div { background: #2e7d32; height: 10px; }
+</style>
+</head>
+<body>
+<h1>Strings dashboard</h1>
+<p>
+<button id="validate">Validate</button>
+<button id="pull">Pull translations</button>
+<span id="state"></span>
+</p>
+<p id="sync"></p>
+<h2>Coverage</h2>
+<table id="locales"></table>
+<h2>Findings</h2>
+<table id="findings"></table>
+<script>
+function escape(s) {
+	var div = document.createElement("div");
+	div.textContent = s;
+	return div.innerHTML;
+}
+
+function render(status) {
+	var state = status.ValidatedAt.indexOf("0001-") == 0 ? "Not validated yet." : "Validated at " + new Date(status.ValidatedAt).toLocaleString() + ".";
+	document.getElementById("state").textContent = state;
+	document.getElementById("pull").disabled = !status.CanPull;
+	var sync = "No pull since the start.";
+	if (status.LastSync) {
+		sync = "Last pull at " + new Date(status.LastSync.Time).toLocaleString() + (status.LastSync.Error ? " failed: " + status.LastSync.Error : " succeeded.");
+		if (status.LastSync.Hint) {
+			sync += " Hint: " + status.LastSync.Hint;
+		}
+	}
+	document.getElementById("sync").textContent = sync;
+
+	var rows = "<tr><th>Locale</th><th>Coverage</th><th>Errors</th><th>Warnings</th><th>Infos</th></tr>";
+	(status.Locales || []).forEach(function (l) {
+		var percent = l.Total > 0 ? Math.floor(100 * l.Translated / l.Total) : 100;
+		rows += "<tr><td>" + escape(l.Locale) + "</td><td><span class=\"bar\"><div style=\"width: " + percent + "%\"></div></span> " +
+			percent + "% (" + l.Translated + "/" + l.Total + ")</td><td class=\"error\">" + (l.Findings.error || 0) +
+			"</td><td class=\"warning\">" + (l.Findings.warning || 0) + "</td><td class=\"info\">" + (l.Findings.info || 0) + "</td></tr>";
+	});
+	document.getElementById("locales").innerHTML = rows;
+
+	rows = "<tr><th>Severity</th><th>File</th><th>Key</th><th>Rule</th><th>Message</th></tr>";
+	(status.Findings || []).forEach(function (f) {
+		rows += "<tr class=\"" + f.Severity + "\"><td>" + f.Severity + "</td><td>" + escape(f.Path) + "</td><td><code>" + escape(f.Key) +
+			"</code></td><td>" + escape(f.Rule) + "</td><td>" + escape(f.Message) + "</td></tr>";
+	});
+	document.getElementById("findings").innerHTML = rows;
+}
+
+function request(method, url) {
+	document.getElementById("state").textContent = "Working...";
+	fetch(url, {method: method}).then(function (response) {
+		if (!response.ok) {
+			return response.text().then(function (text) { throw new Error(text); });
+		}
+		return response.json();
+	}).then(render).catch(function (e) {
+		document.getElementById("state").textContent = e.message;
+	});
+}
+
+document.getElementById("validate").onclick = function () { request("POST", "/api/validate"); };
+document.getElementById("pull").onclick = function () { request("POST", "/api/pull"); };
+request("GET", "/api/status");
+setInterval(function () { request("GET", "/api/status"); }, 30000);
+</script>
+</body>
+</html>
+`
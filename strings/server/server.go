@@ -0,0 +1,208 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/armatys/android-tools/strings/apierr"
+	"github.com/armatys/android-tools/strings/coverage"
+	"github.com/armatys/android-tools/strings/report"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/validator"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Serves a dashboard with the translation coverage and the validation findings of a project,
+// from which the translations can be pulled and validated.
+type Server struct {
+	ResDir     string
+	BaseLocale string
+	Filename   string
+	Options    validator.Options
+	// The address the server listens on, e.g. "localhost:8080"; its host name is accepted
+	// in the Host header of the API requests, besides "localhost" and the IP addresses.
+	Addr string
+	// Pulls the translations from the provider; nil if pulling is not configured.
+	Pull func() error
+
+	mutex       sync.Mutex
+	findings    []report.Finding
+	validatedAt time.Time
+	lastSync    *SyncStatus
+}
+
+// The result of the last pull.
+type SyncStatus struct {
+	Time time.Time
+	// The error of the pull; empty if it succeeded.
+	Error string
+	// How to fix the error, if the provider gave a hint.
+	Hint string `json:",omitempty"`
+}
+
+// The translation coverage and the findings of a locale.
+type LocaleStatus struct {
+	Locale string
+	// The number of the translatable base strings, plurals and arrays, and the number of the translated ones.
+	Total      int
+	Translated int
+	// The number of the findings per severity.
+	Findings map[string]int
+}
+
+// The state of the project shown by the dashboard.
+type Status struct {
+	Locales     []LocaleStatus
+	Findings    []report.Finding
+	ValidatedAt time.Time
+	LastSync    *SyncStatus
+	CanPull     bool
+}
+
+// Returns the HTTP handler of the dashboard and its API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, dashboardHTML)
+	})
+	mux.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "GET required", http.StatusMethodNotAllowed)
+			return
+		}
+		if !s.isServerHost(r.Host) {
+			http.Error(w, "Unknown host", http.StatusForbidden)
+			return
+		}
+		s.writeStatus(w)
+	})
+	mux.HandleFunc("/api/validate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		if !s.isSameOrigin(r) {
+			http.Error(w, "Cross-origin request rejected", http.StatusForbidden)
+			return
+		}
+		s.Validate()
+		s.writeStatus(w)
+	})
+	mux.HandleFunc("/api/pull", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		if !s.isSameOrigin(r) {
+			http.Error(w, "Cross-origin request rejected", http.StatusForbidden)
+			return
+		}
+		if s.Pull == nil {
+			http.Error(w, "Pulling is not configured", http.StatusNotFound)
+			return
+		}
+		s.pull()
+		s.Validate()
+		s.writeStatus(w)
+	})
+	return mux
+}
+
+// Returns true if the request comes from the dashboard served by this server, rather than from another site
+// (a cross-site request forgery) or through a domain name resolving to the local address (a DNS rebinding).
+// The requests without the Origin header (e.g. by curl) are accepted, since the browsers send it with every POST.
+func (s *Server) isSameOrigin(r *http.Request) bool {
+	if !s.isServerHost(r.Host) {
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && strings.EqualFold(u.Host, r.Host)
+}
+
+// Returns true if the `host` (with an optional port) is "localhost", an IP address or the host of the `Addr`.
+func (s *Server) isServerHost(host string) bool {
+	name := hostName(host)
+	if strings.EqualFold(name, "localhost") || net.ParseIP(name) != nil {
+		return true
+	}
+	addrName := hostName(s.Addr)
+	return len(addrName) > 0 && strings.EqualFold(name, addrName)
+}
+
+// Returns the `host` without the port and the brackets of an IPv6 address.
+func hostName(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+}
+
+// Validates the strings and keeps the findings for the dashboard.
+func (s *Server) Validate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	errorList := validator.Validate(s.ResDir, s.BaseLocale, s.Filename, s.Options)
+	s.findings = report.Findings(errorList, s.Options.Config)
+	s.validatedAt = time.Now()
+}
+
+func (s *Server) pull() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	status := &SyncStatus{Time: time.Now()}
+	if err := s.Pull(); err != nil {
+		status.Error = err.Error()
+		if h, ok := err.(apierr.Hinter); ok {
+			status.Hint = h.RemediationHint()
+		}
+	}
+	s.lastSync = status
+}
+
+func (s *Server) writeStatus(w http.ResponseWriter) {
+	s.mutex.Lock()
+	status := Status{Findings: s.findings, ValidatedAt: s.validatedAt, LastSync: s.lastSync, CanPull: s.Pull != nil}
+	s.mutex.Unlock()
+
+	locales, err := s.coverage()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range locales {
+		for _, f := range status.Findings {
+			if locale, _ := resources.FormFactorFromPath(f.Path); locale == locales[i].Locale {
+				locales[i].Findings[f.Severity] += 1
+			}
+		}
+	}
+	status.Locales = locales
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Returns the translation coverage of the locales of the project, sorted by the locale.
+func (s *Server) coverage() ([]LocaleStatus, error) {
+	coverages, err := coverage.Compute(s.ResDir, s.BaseLocale, s.Filename, s.Options.Config)
+	if err != nil {
+		return nil, err
+	}
+	var locales []LocaleStatus
+	for _, c := range coverages {
+		locales = append(locales, LocaleStatus{Locale: c.Locale, Total: c.All.Total, Translated: c.All.Translated, Findings: make(map[string]int)})
+	}
+	return locales, nil
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequestsFromOtherOriginsAreRejected(t *testing.T) {
+	resDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(resDir, "values"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resDir, "values", "strings.xml"), []byte("<resources>\n    <string name=\"greeting\">Hello</string>\n</resources>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		host     string
+		origin   string
+		wantCode int
+	}{
+		{"validate from the dashboard", http.MethodPost, "/api/validate", "localhost:8080", "http://localhost:8080", http.StatusOK},
+		{"validate without an origin", http.MethodPost, "/api/validate", "localhost:8080", "", http.StatusOK},
+		{"validate from an IP address", http.MethodPost, "/api/validate", "127.0.0.1:8080", "http://127.0.0.1:8080", http.StatusOK},
+		{"validate from the listen address", http.MethodPost, "/api/validate", "dashboard.local:8080", "http://dashboard.local:8080", http.StatusOK},
+		{"validate from another site", http.MethodPost, "/api/validate", "localhost:8080", "https://evil.example", http.StatusForbidden},
+		{"validate from another port", http.MethodPost, "/api/validate", "localhost:8080", "http://localhost:9090", http.StatusForbidden},
+		{"validate from an opaque origin", http.MethodPost, "/api/validate", "localhost:8080", "null", http.StatusForbidden},
+		{"validate through a rebound domain", http.MethodPost, "/api/validate", "evil.example:8080", "http://evil.example:8080", http.StatusForbidden},
+		{"status from the dashboard", http.MethodGet, "/api/status", "localhost:8080", "", http.StatusOK},
+		{"status through a rebound domain", http.MethodGet, "/api/status", "evil.example:8080", "", http.StatusForbidden},
+		{"status posted", http.MethodPost, "/api/status", "localhost:8080", "http://localhost:8080", http.StatusMethodNotAllowed},
+		{"pull from the dashboard", http.MethodPost, "/api/pull", "localhost:8080", "http://localhost:8080", http.StatusOK},
+		{"pull from another site", http.MethodPost, "/api/pull", "localhost:8080", "https://evil.example", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pulled := false
+			s := &Server{ResDir: resDir, Filename: "strings.xml", Addr: "dashboard.local:8080", Pull: func() error {
+				pulled = true
+				return errors.New("not pulled in the test")
+			}}
+			request := httptest.NewRequest(test.method, test.path, nil)
+			request.Host = test.host
+			if len(test.origin) > 0 {
+				request.Header.Set("Origin", test.origin)
+			}
+			recorder := httptest.NewRecorder()
+			s.Handler().ServeHTTP(recorder, request)
+			if recorder.Code != test.wantCode {
+				t.Errorf("got the status %d, want %d", recorder.Code, test.wantCode)
+			}
+			if wantPulled := test.path == "/api/pull" && test.wantCode == http.StatusOK; pulled != wantPulled {
+				t.Errorf("got pulled %v, want %v", pulled, wantPulled)
+			}
+		})
+	}
+}
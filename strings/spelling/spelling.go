@@ -0,0 +1,74 @@
+package spelling
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// A misspelled word reported by hunspell.
+type Misspelling struct {
+	Word string
+	// The corrections suggested by hunspell; may be empty.
+	Suggestions []string
+}
+
+// Checks the spelling of the `lines` with the hunspell program and the `dictionary`
+// (a name like "pl_PL" or a path without the .dic/.aff extension); any hunspell-compatible dictionary can be used.
+// Returns the misspelled words of every line.
+func Check(dictionary string, lines []string) ([][]Misspelling, error) {
+	var input bytes.Buffer
+	for _, line := range lines {
+		// The "^" prefix makes hunspell check the line, even if it starts with a pipe mode command.
+		input.WriteString("^" + strings.Replace(line, "\n", " ", -1) + "\n")
+	}
+	cmd := exec.Command("hunspell", "-a", "-i", "utf-8", "-d", dictionary)
+	cmd.Stdin = &input
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("hunspell -d %s: %s %s", dictionary, err.Error(), strings.TrimSpace(stderr.String())))
+	}
+	return parsePipeOutput(out, len(lines))
+}
+
+// Parses the output of the hunspell pipe mode (-a): the version line, followed by the results
+// of every input line, each terminated with an empty line.
+func parsePipeOutput(out []byte, lineCount int) ([][]Misspelling, error) {
+	results := make([][]Misspelling, lineCount)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		return nil, errors.New("hunspell did not print its version")
+	}
+	line := 0
+	for scanner.Scan() && line < lineCount {
+		text := scanner.Text()
+		if len(text) == 0 {
+			line++
+			continue
+		}
+		switch text[0] {
+		case '&':
+			// & word count offset: suggestion, suggestion
+			parts := strings.SplitN(text, ": ", 2)
+			fields := strings.Fields(parts[0])
+			misspelling := Misspelling{Word: fields[1]}
+			if len(parts) == 2 {
+				misspelling.Suggestions = strings.Split(parts[1], ", ")
+			}
+			results[line] = append(results[line], misspelling)
+		case '#':
+			// # word offset
+			results[line] = append(results[line], Misspelling{Word: strings.Fields(text)[1]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,263 @@
+package triage
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/armatys/android-tools/strings/locales"
+	"github.com/armatys/android-tools/strings/report"
+	"github.com/armatys/android-tools/strings/resources"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The width of each column of the base text and the translation shown side by side.
+const columnWidth = 38
+
+// A finding to review, with the res directory of its module.
+type Item struct {
+	Finding report.Finding
+	ResDir  string
+}
+
+// The findings of the same module, file and rule, which are next to each other in the session.
+type group struct {
+	title string
+	// the index of the first finding of the group, and the number of its findings
+	start, count int
+}
+
+// An interactive review of the findings in a terminal: the findings are listed by the locale and the rule,
+// shown one at a time with the base text and the translation side by side, and can be added to the baseline file.
+type Session struct {
+	items           []Item
+	groups          []group
+	baseLocale      string
+	stringsFilename string
+	baselinePath    string
+	displayLanguage string
+	ignored         map[int]bool
+	current         int
+	// the texts of the resources of the strings files, keyed by the path of the file
+	texts map[string]map[string]string
+}
+
+// Returns a session reviewing the `items`; the ignored findings are added to the baseline file at `baselinePath`.
+// The locale names are shown in the `displayLanguage` (e.g. "en"), or not shown if it is empty.
+func NewSession(items []Item, baseLocale, stringsFilename, baselinePath, displayLanguage string) *Session {
+	sorted := append([]Item{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Finding, sorted[j].Finding
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Key < b.Key
+	})
+	s := &Session{items: sorted, baseLocale: baseLocale, stringsFilename: stringsFilename, baselinePath: baselinePath,
+		displayLanguage: displayLanguage, ignored: make(map[int]bool), texts: make(map[string]map[string]string)}
+	for i, item := range sorted {
+		f := item.Finding
+		title := fmt.Sprintf("%s, %s", locales.DescribePath(f.Path, displayLanguage), f.Rule)
+		if len(f.Module) > 0 {
+			title = fmt.Sprintf("%s %s", f.Module, title)
+		}
+		if n := len(s.groups); n > 0 && s.groups[n-1].title == title {
+			s.groups[n-1].count += 1
+		} else {
+			s.groups = append(s.groups, group{title, i, 1})
+		}
+	}
+	return s
+}
+
+// Runs the session, reading the commands from `in` and writing to `out`, until the "q" command or the end of the input.
+func (s *Session) Run(in io.Reader, out io.Writer) error {
+	if len(s.items) == 0 {
+		fmt.Fprintln(out, "No findings to triage.")
+		return nil
+	}
+	scanner := bufio.NewScanner(in)
+	s.listGroups(out)
+	s.show(out)
+	for {
+		fmt.Fprint(out, "[n]ext, [p]revious, [ / ] group, [g]roup N, N, [i]gnore, [l]ist, [q]uit > ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		command := strings.Fields(scanner.Text())
+		if len(command) == 0 {
+			command = []string{"n"}
+		}
+		switch command[0] {
+		case "n":
+			s.move(1)
+		case "p":
+			s.move(-1)
+		case "]":
+			s.moveGroup(1)
+		case "[":
+			s.moveGroup(-1)
+		case "g":
+			if n, err := strconv.Atoi(strings.Join(command[1:], "")); err == nil && n >= 1 && n <= len(s.groups) {
+				s.current = s.groups[n-1].start
+			} else {
+				fmt.Fprintf(out, "Enter a group number from 1 to %d.\n", len(s.groups))
+				continue
+			}
+		case "i":
+			if s.ignored[s.current] {
+				fmt.Fprintln(out, "The finding is already in the baseline.")
+				continue
+			}
+			if err := report.AppendBaseline(s.baselinePath, []report.Finding{s.items[s.current].Finding}); err != nil {
+				return err
+			}
+			s.ignored[s.current] = true
+			fmt.Fprintf(out, "Added to the baseline %s.\n", s.baselinePath)
+			s.move(1)
+		case "l":
+			s.listGroups(out)
+			continue
+		case "q":
+			return nil
+		default:
+			if n, err := strconv.Atoi(command[0]); err == nil && n >= 1 && n <= len(s.items) {
+				s.current = n - 1
+			} else {
+				fmt.Fprintf(out, "Unknown command '%s'.\n", command[0])
+				continue
+			}
+		}
+		s.show(out)
+	}
+}
+
+// Moves to the finding `delta` positions from the current one, within the bounds.
+func (s *Session) move(delta int) {
+	s.current += delta
+	if s.current < 0 {
+		s.current = 0
+	}
+	if s.current >= len(s.items) {
+		s.current = len(s.items) - 1
+	}
+}
+
+// Moves to the first finding of the group `delta` groups from the group of the current finding.
+func (s *Session) moveGroup(delta int) {
+	for i, g := range s.groups {
+		if s.current >= g.start && s.current < g.start+g.count {
+			i += delta
+			if i >= 0 && i < len(s.groups) {
+				s.current = s.groups[i].start
+			}
+			return
+		}
+	}
+}
+
+// Writes the groups of the findings with their numbers and the numbers of their findings.
+func (s *Session) listGroups(out io.Writer) {
+	for i, g := range s.groups {
+		ignoredCount := 0
+		for j := g.start; j < g.start+g.count; j++ {
+			if s.ignored[j] {
+				ignoredCount += 1
+			}
+		}
+		fmt.Fprintf(out, "%3d. %s: %d finding(s)", i+1, g.title, g.count)
+		if ignoredCount > 0 {
+			fmt.Fprintf(out, ", %d ignored", ignoredCount)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// Writes the current finding, with the base text and the translation side by side.
+func (s *Session) show(out io.Writer) {
+	item := s.items[s.current]
+	f := item.Finding
+	fmt.Fprintf(out, "\n[%d/%d] ", s.current+1, len(s.items))
+	if len(f.Module) > 0 {
+		fmt.Fprintf(out, "%s ", f.Module)
+	}
+	fmt.Fprintf(out, "%s, %s (%s)", locales.DescribePath(f.Path, s.displayLanguage), f.Rule, f.Severity)
+	if s.ignored[s.current] {
+		fmt.Fprint(out, " [ignored]")
+	}
+	fmt.Fprintf(out, "\n%s\n", f.Message)
+	if len(f.Key) == 0 {
+		return
+	}
+	basePath := filepath.Join(resources.ValuesDir(s.baseLocale), s.stringsFilename)
+	baseText, ok := s.textsOf(item.ResDir, basePath)[f.Key]
+	if !ok {
+		baseText = "(none)"
+	}
+	if f.Path == basePath {
+		fmt.Fprintf(out, "\n  %s\n", strings.Join(wrap(baseText, 2*columnWidth+3), "\n  "))
+		return
+	}
+	text, ok := s.textsOf(item.ResDir, f.Path)[f.Key]
+	if !ok {
+		text = "(missing)"
+	}
+	left := append([]string{"Base", strings.Repeat("-", columnWidth)}, wrap(baseText, columnWidth)...)
+	right := append([]string{"Translation", strings.Repeat("-", columnWidth)}, wrap(text, columnWidth)...)
+	fmt.Fprintln(out)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		fmt.Fprintf(out, "  %s%s | %s\n", l, strings.Repeat(" ", columnWidth-len([]rune(l))), r)
+	}
+}
+
+// Returns the texts of the resources of the strings file at `shortPath` in the `resDir`,
+// or an empty map if the file cannot be read.
+func (s *Session) textsOf(resDir, shortPath string) map[string]string {
+	path := filepath.Join(resDir, shortPath)
+	if texts, ok := s.texts[path]; ok {
+		return texts
+	}
+	texts := make(map[string]string)
+	if res, err := resources.ParseFile(path); err == nil {
+		texts = res.Texts()
+	}
+	s.texts[path] = texts
+	return texts
+}
+
+// Splits the `text` into the lines of at most `width` characters, breaking the lines at the spaces if possible.
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := []rune(paragraph)
+		for len(runes) > width {
+			end := width
+			for i := width; i > width/2; i-- {
+				if runes[i] == ' ' {
+					end = i
+					break
+				}
+			}
+			lines = append(lines, string(runes[:end]))
+			runes = []rune(strings.TrimLeft(string(runes[end:]), " "))
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
@@ -0,0 +1,142 @@
+package usage
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+)
+
+// A single place in the source code that refers to a string resource.
+type Reference struct {
+	Path string
+	Line int
+}
+
+var codeReferenceRegex *regexp.Regexp = regexp.MustCompile("R\\.string\\.([a-zA-Z0-9_]+)")
+var xmlReferenceRegex *regexp.Regexp = regexp.MustCompile("@string/([a-zA-Z0-9_.]+)")
+
+// Matches `getIdentifier("name", "string", ...)` and `getIdentifier("prefix_" + x, "string", ...)` calls.
+var identifierLookupRegex *regexp.Regexp = regexp.MustCompile("getIdentifier\\(\\s*\"([a-zA-Z0-9_.]*)\"\\s*(\\+[^,]*)?,\\s*\"string\"")
+
+// Extensions of the files that are scanned for string references.
+var scannedExtensions = map[string]bool{".java": true, ".kt": true, ".xml": true}
+
+// Extensions of the source code files.
+var codeExtensions = map[string]bool{".java": true, ".kt": true}
+
+// The maximal length of a scanned line; the generated or minified sources may have lines longer than the 64 KB
+// the scanner accepts by default.
+const maxLineLength = 16 * 1024 * 1024
+
+// A type of function that finds resource names in a single line of the file at `path`.
+type lineMatcher func(path, line string) []string
+
+// Walks the `dir` directory and finds all references to string resources
+// in Java, Kotlin and XML files. Returns a map from a resource name to the places it is referenced from.
+func FindStringReferences(dir string) (map[string][]Reference, error) {
+	return scanDir(dir, scannedExtensions, matchStringReferences)
+}
+
+// Walks the `dir` directory and finds all string lookups by name done with `Resources.getIdentifier`
+// in Java and Kotlin files. Returns a map from a resource name to the places it is looked up from.
+// If the name is built dynamically (e.g. `"prefix_" + suffix`), the map key is a glob like "prefix_*".
+func FindIdentifierLookups(dir string) (map[string][]Reference, error) {
+	return scanDir(dir, codeExtensions, matchIdentifierLookups)
+}
+
+// Returns the `names` of the strings which are neither referenced (with R.string or @string) in the files
+// of the `dir` directory, nor looked up by name with `Resources.getIdentifier`, nor match any of the `dynamicKeys`
+// (the names or glob patterns of the strings looked up in another way), in the order of the `names`.
+func FindUnused(dir string, names []string, dynamicKeys []string) ([]string, error) {
+	references, err := FindStringReferences(dir)
+	if err != nil {
+		return nil, err
+	}
+	lookups, err := FindIdentifierLookups(dir)
+	if err != nil {
+		return nil, err
+	}
+	patterns := dynamicKeys
+	for pattern := range lookups {
+		patterns = append(patterns, pattern)
+	}
+	var unused []string
+	for _, name := range names {
+		if _, ok := references[name]; ok || matchesAny(name, patterns) {
+			continue
+		}
+		unused = append(unused, name)
+	}
+	return unused, nil
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchStringReferences(path, line string) []string {
+	regex := codeReferenceRegex
+	if filepath.Ext(path) == ".xml" {
+		regex = xmlReferenceRegex
+	}
+	var names []string
+	for _, match := range regex.FindAllStringSubmatch(line, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func matchIdentifierLookups(path, line string) []string {
+	var names []string
+	for _, match := range identifierLookupRegex.FindAllStringSubmatch(line, -1) {
+		if len(match[2]) > 0 {
+			names = append(names, match[1]+"*")
+		} else {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+func scanDir(dir string, extensions map[string]bool, matcher lineMatcher) (map[string][]Reference, error) {
+	references := make(map[string][]Reference)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !extensions[filepath.Ext(path)] {
+			return nil
+		}
+		return scanFile(path, references, matcher)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+func scanFile(path string, references map[string][]Reference, matcher lineMatcher) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		for _, name := range matcher(path, scanner.Text()) {
+			references[name] = append(references[name], Reference{path, lineNumber})
+		}
+	}
+	return scanner.Err()
+}
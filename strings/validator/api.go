@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"context"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// Validates the strings files of a res directory, for the Go tools embedding the checks.
+// Unlike the command line tool, it neither prints nor exits, and returns the problems as findings.
+type Validator struct {
+	// The path to the Android's "res" directory.
+	ResDir string
+	// The locale of the base strings file, e.g. "" for the "values" directory.
+	BaseLocale string
+	// The name of the strings file, e.g. "strings.xml".
+	Filename string
+	Options  Options
+}
+
+// A problem found by the `Validator`.
+type Finding struct {
+	// The name of the resource; empty for the problems not related to a resource (e.g. of a values directory).
+	Key string
+	// The locale of the file, e.g. "de" or "pt-rBR" (the `Validator.BaseLocale` for the base file and its overlays).
+	Locale string
+	// The short path of the file, e.g. "values-de/strings.xml"; for a file set, the file declaring the resource (if known).
+	File string
+	// The line and the column (starting with 1) of the resource in the file; zero if unknown.
+	Line   int
+	Column int
+	// The ID of the rule, e.g. "markup" or "missing".
+	RuleID string
+	// The severity of the rule according to the `Options.Config`: "error", "warning" or "info".
+	Severity string
+	Message  string
+	// The suggested replacement of the raw value of the string or the item; empty if the rule has no mechanical fix.
+	Fix string
+}
+
+// Validates the strings files and returns the findings sorted by the file (i.e. the locale), the key and the rule.
+// Returns an error if a file could not be read or parsed, if the `Options.Deadline` passed
+// (a `DeadlineExceededError`), or if the `ctx` is done (its error); the findings of the checked files
+// are returned in any case.
+func (v *Validator) Validate(ctx context.Context) ([]Finding, error) {
+	var findings []Finding
+	var firstError error
+	for _, e := range validate(ctx, v.ResDir, v.BaseLocale, v.Filename, v.Options) {
+		path, key, rule := errorLocation(e)
+		if len(rule) == 0 {
+			if firstError == nil {
+				firstError = e
+			}
+			continue
+		}
+		locale := resources.LocaleFromPath(path)
+		if q, err := resources.ParseQualifiers(filepath.Dir(path)); err == nil {
+			locale = q.Locale
+		}
+		if len(locale) == 0 {
+			locale = v.BaseLocale
+		}
+		finding := Finding{
+			Key:      key,
+			Locale:   locale,
+			File:     path,
+			RuleID:   rule,
+			Severity: v.Options.Config.SeverityOf(e),
+			Message:  e.Error(),
+		}
+		if ve, ok := e.(*ValidationError); ok {
+			finding.Fix = ve.Fix
+		}
+		if position := PositionOf(e); position != nil {
+			finding.File, finding.Line, finding.Column = position.File, position.Line, position.Column
+		}
+		findings = append(findings, finding)
+	}
+	if _, ok := firstError.(*DeadlineExceededError); ok && ctx.Err() != nil {
+		firstError = ctx.Err()
+	}
+	return findings, firstError
+}
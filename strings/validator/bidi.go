@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The names of the Unicode bidirectional control characters.
+var bidiControls = map[rune]string{
+	'\u061c': "ALM",
+	'\u200e': "LRM",
+	'\u200f': "RLM",
+	'\u202a': "LRE",
+	'\u202b': "RLE",
+	'\u202c': "PDF",
+	'\u202d': "LRO",
+	'\u202e': "RLO",
+	'\u2066': "LRI",
+	'\u2067': "RLI",
+	'\u2068': "FSI",
+	'\u2069': "PDI",
+}
+
+// The bidi marks, which only influence the direction of the neighbouring characters;
+// they are reported only in the strict mode.
+var bidiMarks = map[rune]bool{'\u061c': true, '\u200e': true, '\u200f': true}
+
+// The languages written from right to left.
+var rtlLanguages = map[string]bool{
+	"ar": true, "ckb": true, "dv": true, "fa": true, "he": true, "iw": true,
+	"ps": true, "sd": true, "ug": true, "ur": true, "yi": true, "ji": true,
+}
+
+// Matches the escaped bidi control characters, e.g. "\u200F".
+var escapedBidiControlRegex *regexp.Regexp = regexp.MustCompile("(?i)\\\\u(061c|200[ef]|202[a-e]|206[6-9])")
+
+// Matches the string placeholders, which may be replaced with a text in another direction, e.g. "%s" or "%1$s".
+var stringPlaceholderRegex *regexp.Regexp = regexp.MustCompile("%([0-9]+\\$)?s")
+
+// Returns a validation function reporting the raw bidi control characters, which are invisible in the editors.
+// In the `strict` mode, the raw bidi marks (LRM, RLM, ALM) are reported as well, and for the RTL `locale`
+// the string placeholders without any bidi control character, which may display in the wrong order.
+func bidiValidation(locale string, strict bool) simpleValidation {
+	rtl := rtlLanguages[languageOf(locale)]
+	return func(elemValue string) error {
+		var found []string
+		for i, r := range []rune(elemValue) {
+			name, ok := bidiControls[r]
+			if !ok || (bidiMarks[r] && !strict) {
+				continue
+			}
+			found = append(found, fmt.Sprintf("%s (U+%04X) at position %d", name, r, i))
+		}
+		if len(found) > 0 {
+			return errors.New(fmt.Sprintf("The value has raw bidi control character(s): %s; use the escaped form (e.g. \\u200F) instead", strings.Join(found, ", ")))
+		}
+		if strict && rtl && stringPlaceholderRegex.MatchString(elemValue) && !escapedBidiControlRegex.MatchString(elemValue) {
+			return errors.New("The value has a string placeholder, which may contain a left-to-right text, but no bidi control characters; isolate the placeholder with \\u2068 and \\u2069 (FSI and PDI)")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/brands"
+	"github.com/armatys/android-tools/strings/resources"
+)
+
+// Validates that the values in `res` use only the brand variables declared in `b`,
+// and that they do not hardcode a brand-specific value instead of using a variable.
+func validateBrandVariables(res *resources.Resources, shortPath string, b brands.Brands) []error {
+	var errorList []error
+	validateValue := func(name, value string) {
+		for _, match := range brands.VariableRegex.FindAllStringSubmatch(value, -1) {
+			if !b.IsDeclared(match[1]) {
+				errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The brand variable {%s} is not declared", name, shortPath, match[1]), shortPath, name, RuleBrandVariables, nil, ""})
+			}
+		}
+		if variable, brand := b.FindHardcodedValue(value); len(variable) > 0 {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The value of {%s} for the brand '%s' is hardcoded, while the variable should be used", name, shortPath, variable, brand), shortPath, name, RuleBrandVariables, nil, ""})
+		}
+	}
+
+	for _, el := range res.Strings {
+		validateValue(el.Name, el.Value)
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			validateValue(el.Name, item.Value)
+		}
+	}
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			validateValue(el.Name, item.Value)
+		}
+	}
+	return errorList
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+	"unicode"
+)
+
+// Configuration of the casing checks of short strings (e.g. buttons or titles).
+type CasingConfig struct {
+	// Glob patterns (e.g. "button_*" or "*_title") of the names of the strings to check.
+	Keys []string
+	// Languages (e.g. "de") in which the nouns are capitalized, so a translation may look like a title case.
+	// Defaults to German and Luxembourgish.
+	CapitalizedNounLanguages []string
+}
+
+// The casing styles of a string.
+const (
+	casingUnknown  = ""
+	casingUpper    = "all-caps"
+	casingLower    = "all-lowercase"
+	casingTitle    = "title case"
+	casingSentence = "sentence case"
+)
+
+var defaultCapitalizedNounLanguages = []string{"de", "lb"}
+
+// Languages whose scripts do not have letter case; the casing of the strings in these languages is not checked.
+var caselessLanguages = map[string]bool{
+	"am": true, "ar": true, "bn": true, "fa": true, "gu": true, "he": true, "hi": true, "iw": true,
+	"ja": true, "ka": true, "km": true, "kn": true, "ko": true, "lo": true, "ml": true, "mr": true,
+	"my": true, "ne": true, "pa": true, "si": true, "ta": true, "te": true, "th": true, "ur": true, "zh": true,
+}
+
+// Returns the language of the `locale`, e.g. "pt" for "pt-rBR" or "sr" for "b+sr+Latn".
+func languageOf(locale string) string {
+	locale = strings.TrimPrefix(locale, "b+")
+	if i := strings.IndexAny(locale, "-+"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// Returns the casing style of the string `s`, or `casingUnknown` if it cannot be determined.
+// A string is in the title case, if every word longer than 3 letters starts with an upper case letter.
+func casingStyle(s string) string {
+	upper, lower := 0, 0
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			upper += 1
+		} else if unicode.IsLower(r) {
+			lower += 1
+		}
+	}
+	if upper+lower < 2 {
+		return casingUnknown
+	}
+	if lower == 0 {
+		return casingUpper
+	}
+	if upper == 0 {
+		return casingLower
+	}
+	words := strings.Fields(s)
+	first := []rune(words[0])
+	if !unicode.IsUpper(first[0]) {
+		return casingUnknown
+	}
+	for _, word := range words[1:] {
+		runes := []rune(word)
+		if len(runes) > 3 && unicode.IsLetter(runes[0]) && !unicode.IsUpper(runes[0]) {
+			return casingSentence
+		}
+	}
+	if len(words) > 1 {
+		return casingTitle
+	}
+	return casingSentence
+}
+
+// Compares the casing style of the `validatedValue` with the `baseValue`.
+// The title case is accepted in the translation if `capitalizedNouns` is true.
+func validateCasing(baseValue, validatedValue string, capitalizedNouns bool) error {
+	baseStyle := casingStyle(baseValue)
+	targetStyle := casingStyle(validatedValue)
+	if baseStyle == casingUnknown || targetStyle == casingUnknown || baseStyle == targetStyle {
+		return nil
+	}
+	switch {
+	case baseStyle == casingUpper || targetStyle == casingUpper || targetStyle == casingLower:
+		return errors.New(fmt.Sprintf("The target string is in %s, while the base string is in %s", targetStyle, baseStyle))
+	case baseStyle == casingSentence && targetStyle == casingTitle && !capitalizedNouns:
+		return errors.New(fmt.Sprintf("The target string is in %s, while the base string is in %s", targetStyle, baseStyle))
+	}
+	return nil
+}
+
+// Validates the casing of the strings (in the `locale`) matching the `casing.Keys` patterns.
+func validateCasingOfResources(baseResources, validatedResources *resources.Resources, locale, shortPath string, casing *CasingConfig) []error {
+	language := languageOf(locale)
+	if caselessLanguages[language] {
+		return nil
+	}
+	nounLanguages := casing.CapitalizedNounLanguages
+	if nounLanguages == nil {
+		nounLanguages = defaultCapitalizedNounLanguages
+	}
+	capitalizedNouns := containsString(nounLanguages, language)
+
+	var errorList []error
+	for _, baseElem := range baseResources.Strings {
+		if !matchesAnyPattern(baseElem.Name, casing.Keys) {
+			continue
+		}
+		validatedElem := validatedResources.FindString(baseElem.Name)
+		if validatedElem == nil {
+			continue
+		}
+		if err := validateCasing(baseElem.Value, validatedElem.Value, capitalizedNouns); err != nil {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: %s", baseElem.Name, shortPath, err.Error()), shortPath, baseElem.Name, RuleCasing, nil, ""})
+		}
+	}
+	return errorList
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"github.com/armatys/android-tools/strings/git"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// The strings files and the base strings changed since a git revision, to which the validation is limited.
+type Changes struct {
+	// The short paths of the changed files (e.g. "values-de/strings.xml").
+	Files map[string]bool
+	// The names of the base strings, plurals and arrays that were added or changed.
+	Keys map[string]bool
+	// The short path of the base strings file, whose errors are limited to the changed `Keys`.
+	basePath string
+}
+
+// Returns the changes of the strings files in the `resDir` since the git revision `ref` (e.g. "origin/main").
+func ChangesSince(resDir, baseLocale, stringsFilename, ref string) (*Changes, error) {
+	paths, err := git.ChangedFiles(resDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	basePath := filepath.Join(resources.ValuesDir(baseLocale), stringsFilename)
+	changes := &Changes{make(map[string]bool), make(map[string]bool), basePath}
+	for _, path := range paths {
+		changes.Files[path] = true
+	}
+	if !changes.includes(basePath) {
+		return changes, nil
+	}
+	current, err := resources.Parse(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return nil, err
+	}
+	previousTexts, err := textsAt(resDir, ref, filepath.Join(resDir, basePath))
+	if err != nil {
+		return nil, err
+	}
+	for name, text := range current.Texts() {
+		if previousText, ok := previousTexts[name]; !ok || previousText != text {
+			changes.Keys[name] = true
+		}
+	}
+	return changes, nil
+}
+
+// Returns true if the strings file at `shortPath` changed; a file set (see `resources.IsFileSet`)
+// changed if any file in its values directory changed.
+func (c *Changes) includes(shortPath string) bool {
+	if !resources.IsFileSet(filepath.Base(shortPath)) {
+		return c.Files[shortPath]
+	}
+	for path := range c.Files {
+		if filepath.Dir(path) == filepath.Dir(shortPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the `errorList` without the errors of the unchanged files (and of the unchanged strings of the base file),
+// except for the errors of the changed base keys.
+func (c *Changes) filter(errorList []error) []error {
+	var filtered []error
+	for _, e := range errorList {
+		path, key, _ := errorLocation(e)
+		if (path != c.basePath && c.includes(path)) || c.Keys[key] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
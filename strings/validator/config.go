@@ -0,0 +1,249 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"os"
+)
+
+// IDs of the validation rules, used to enable or disable the rules in the configuration.
+const (
+	RuleNoBaseValue            = "no-base-value"
+	RuleMissing                = "missing"
+	RuleArraySize              = "array-size"
+	RuleSimplePlaceholders     = "simple-placeholders"
+	RulePositionalPlaceholders = "positional-placeholders"
+	RulePotentialPlaceholder   = "potential-placeholder"
+	RuleNewline                = "newline"
+	RuleMarkup                 = "markup"
+	RuleXliff                  = "xliff"
+	RuleUnescapedQuotes        = "unescaped-quotes"
+	RuleTypography             = "typography"
+	RuleBrandVariables         = "brand-variables"
+	RuleNonTranslatable        = "non-translatable"
+	RuleStringReuse            = "string-reuse"
+	RuleCasing                 = "casing"
+	RuleDuplicateName          = "duplicate-name"
+	RuleEmptyTranslation       = "empty-translation"
+	RuleIdenticalToBase        = "identical-to-base"
+	RuleToolsLocale            = "tools-locale"
+	RuleWhitespace             = "whitespace"
+	RuleIOSSpecifiers          = "ios-specifiers"
+	RuleBarePercent            = "bare-percent"
+	RulePercentSafety          = "percent-safety"
+	RuleEscapeParity           = "escape-parity"
+	RuleStringReference        = "string-reference"
+	RuleMaxLength              = "max-length"
+	RuleEndingPunctuation      = "ending-punctuation"
+	RuleLinks                  = "links"
+	RuleNumbers                = "numbers"
+	RuleBidi                   = "bidi"
+	RuleInvisibleCharacters    = "invisible-characters"
+	RuleNormalization          = "normalization"
+	RuleGlossary               = "glossary"
+	RuleSpelling               = "spelling"
+	RuleLocaleDirectory        = "locale-directory"
+	RulePluralQuantities       = "plural-quantities"
+	RulePluralOther            = "plural-other"
+	RuleFeatureIsolation       = "feature-isolation"
+	RuleShrinkSafety           = "shrink-safety"
+	RuleSortedNames            = "sorted-names"
+	RuleStaleTranslation       = "stale-translation"
+	RuleTabCharacters          = "tab-characters"
+	RuleNonBreakingSpace       = "non-breaking-space"
+	RuleQuoteStyle             = "quote-style"
+	RulePlaceholderIndexing    = "placeholder-indexing"
+)
+
+// The validator configuration, read from a JSON file like:
+// {"Rules": {"markup": false}, "Locales": ["de", "fr"], "Profiles": {"release": {"Rules": {"markup": true}}}}
+type Config struct {
+	// Enables (true) or disables (false) the rules by their IDs.
+	// The rules not listed here are enabled, unless they are opt-in.
+	Rules map[string]bool
+	// The locales to validate (e.g. "de", "pt-rBR" or "b+sr+Latn"); all locales are validated if empty.
+	Locales []string
+	// The locales not to validate, even if listed in `Locales`.
+	ExcludeLocales []string
+	// Typographic style conventions per locale (e.g. "de"); the "*" entry applies to the locales not listed.
+	Typography map[string]*TypographyConfig
+	// The ending punctuation conventions per locale or language (e.g. "el"), for the "ending-punctuation" rule;
+	// the "*" entry applies to the locales not listed.
+	Punctuation map[string]*PunctuationConfig
+	// The policies of the "non-breaking-space" rule per locale or language (e.g. "fr"), `NonBreakingSpaceForbid`
+	// or `NonBreakingSpaceRequire`; the "*" entry applies to the locales not listed. The rule is not run for the other locales.
+	NonBreakingSpace map[string]string
+	// If true, the "bidi" rule also reports the raw bidi marks (e.g. RLM), and the string placeholders
+	// without bidi isolation in the right-to-left locales; nil (not set) means false.
+	// A pointer, so that a profile can set it back to false.
+	StrictBidi *bool
+	// The spellchecking of the translations; the spelling is not checked if nil.
+	Spelling *SpellingConfig
+	// The values that may be the same in the base and the translations (e.g. brand names), for the "identical-to-base" rule.
+	IdenticalAllowed []string
+	// The casing checks of short strings; the casing is not checked if nil.
+	Casing *CasingConfig
+	// Project-specific rules matching the values with regular expressions.
+	CustomRules []*CustomRule
+	// The minimal similarity (0-1) of two base values, for which the "string-reuse" rule suggests reusing the existing string.
+	// Defaults to 0.9.
+	ReuseThreshold float64
+	// The minimal confidence (0-1) of a suggested translation (see `Suggestion`), for which it does not need a review.
+	// Defaults to 0.95.
+	AutoApproveConfidence float64
+	// The maximal lengths of the strings, keyed by the name or a glob pattern (e.g. "notification_*").
+	// The length can also be declared with a <!-- maxLength=40 --> comment preceding the base string.
+	MaxLengths map[string]int
+	// Overrides the severities ("error", "warning" or "info") of the rules by their IDs.
+	Severities map[string]string
+	// The names or glob patterns (e.g. "legal_*") of the resources skipped by all the rules,
+	// e.g. the strings which intentionally differ between the locales.
+	Ignore []string
+	// The path to a file with more such names, one per line; the lines starting with "#" are comments.
+	IgnoreFile string
+	// Named profiles (e.g. "ci", "local" or "release") that override the configuration.
+	Profiles map[string]*Config
+}
+
+// IDs of the rules that are disabled unless enabled in the configuration.
+var optInRules = map[string]bool{
+	RuleStringReuse:       true,
+	RuleIdenticalToBase:   true,
+	RuleEndingPunctuation: true,
+	RuleSortedNames:       true,
+}
+
+// Reads the configuration from the JSON file at `path` and applies the `profile` (if not empty).
+func LoadConfig(path, profile string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var config Config
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.compileCustomRules(); err != nil {
+		return nil, err
+	}
+	if err := config.checkSeverities(); err != nil {
+		return nil, err
+	}
+	if err := config.checkNonBreakingSpace(); err != nil {
+		return nil, err
+	}
+	if err := config.loadIgnoreFiles(); err != nil {
+		return nil, err
+	}
+	if len(profile) == 0 {
+		return &config, nil
+	}
+	return config.WithProfile(profile)
+}
+
+// Returns a copy of the configuration with the values overridden by the profile named `name`.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, errors.New(fmt.Sprintf("The profile '%s' is not defined in the configuration.", name))
+	}
+	merged := *c
+	merged.Rules = make(map[string]bool)
+	for id, enabled := range c.Rules {
+		merged.Rules[id] = enabled
+	}
+	for id, enabled := range profile.Rules {
+		merged.Rules[id] = enabled
+	}
+	merged.Severities = make(map[string]string)
+	for id, severity := range c.Severities {
+		merged.Severities[id] = severity
+	}
+	for id, severity := range profile.Severities {
+		merged.Severities[id] = severity
+	}
+	if profile.Locales != nil {
+		merged.Locales = profile.Locales
+	}
+	if profile.ExcludeLocales != nil {
+		merged.ExcludeLocales = profile.ExcludeLocales
+	}
+	if profile.Ignore != nil {
+		merged.Ignore = profile.Ignore
+	}
+	if profile.Typography != nil {
+		merged.Typography = profile.Typography
+	}
+	if profile.CustomRules != nil {
+		merged.CustomRules = profile.CustomRules
+	}
+	if profile.Punctuation != nil {
+		merged.Punctuation = profile.Punctuation
+	}
+	if profile.NonBreakingSpace != nil {
+		merged.NonBreakingSpace = profile.NonBreakingSpace
+	}
+	if profile.StrictBidi != nil {
+		merged.StrictBidi = profile.StrictBidi
+	}
+	if profile.Spelling != nil {
+		merged.Spelling = profile.Spelling
+	}
+	if profile.IdenticalAllowed != nil {
+		merged.IdenticalAllowed = profile.IdenticalAllowed
+	}
+	if profile.Casing != nil {
+		merged.Casing = profile.Casing
+	}
+	if profile.ReuseThreshold > 0 {
+		merged.ReuseThreshold = profile.ReuseThreshold
+	}
+	if profile.MaxLengths != nil {
+		merged.MaxLengths = profile.MaxLengths
+	}
+	if profile.AutoApproveConfidence > 0 {
+		merged.AutoApproveConfidence = profile.AutoApproveConfidence
+	}
+	return &merged, nil
+}
+
+// Returns true if the rule with the `id` should be run.
+func (c *Config) IsRuleEnabled(id string) bool {
+	if c != nil {
+		if enabled, ok := c.Rules[id]; ok {
+			return enabled
+		}
+	}
+	return !optInRules[id]
+}
+
+// Returns true if the "bidi" rule is strict (see `StrictBidi`).
+func (c *Config) IsStrictBidi() bool {
+	return c != nil && c.StrictBidi != nil && *c.StrictBidi
+}
+
+// Returns true if the locale file at `shortPath` (e.g. "values-de/strings.xml") should be validated.
+func (c *Config) IsLocaleIncluded(shortPath string) bool {
+	if c == nil {
+		return true
+	}
+	locale := resources.LocaleFromPath(shortPath)
+	for _, l := range c.ExcludeLocales {
+		if resources.SameLocale(l, locale) {
+			return false
+		}
+	}
+	if len(c.Locales) == 0 {
+		return true
+	}
+	for _, l := range c.Locales {
+		if resources.SameLocale(l, locale) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileOverridesStrictBidi(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validator.json")
+	content := `{
+	"StrictBidi": true,
+	"Profiles": {
+		"lenient": {"StrictBidi": false},
+		"unset": {}
+	}
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		profile string
+		want    bool
+	}{
+		{"", true},
+		{"lenient", false},
+		{"unset", true},
+	}
+	for _, test := range tests {
+		config, err := LoadConfig(path, test.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := config.IsStrictBidi(); got != test.want {
+			t.Errorf("profile %q: got the strict bidi %v, want %v", test.profile, got, test.want)
+		}
+	}
+}
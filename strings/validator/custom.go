@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"regexp"
+)
+
+// The scopes of the custom rules.
+const (
+	ScopeBase        = "base"
+	ScopeTranslation = "translation"
+	ScopeBoth        = "both"
+)
+
+// A project-specific rule, which reports the values matching the `Pattern`, e.g.
+// {"ID": "no-todo", "Pattern": "(?i)\\bTODO\\b", "Scope": "both", "Severity": "warning", "Message": "Remove the TODO marker"}
+type CustomRule struct {
+	// The ID of the rule, used to enable or disable it like the built-in rules.
+	ID string
+	// The regular expression; the values matching it are reported.
+	Pattern string
+	// Which values are checked: "base", "translation" or "both" (the default).
+	Scope string
+	// "error" (the default), "warning" or "info".
+	Severity string
+	// The message reported for the matching values.
+	Message string
+
+	regex *regexp.Regexp
+}
+
+// Compiles the pattern and checks the scope and the severity of the rule.
+func (r *CustomRule) compile() error {
+	if len(r.ID) == 0 {
+		return errors.New(fmt.Sprintf("The custom rule with the pattern '%s' does not have an ID.", r.Pattern))
+	}
+	switch r.Scope {
+	case "", ScopeBase, ScopeTranslation, ScopeBoth:
+	default:
+		return errors.New(fmt.Sprintf("The custom rule '%s' has an unknown scope '%s'.", r.ID, r.Scope))
+	}
+	switch r.Severity {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return errors.New(fmt.Sprintf("The custom rule '%s' has an unknown severity '%s'.", r.ID, r.Severity))
+	}
+	regex, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return errors.New(fmt.Sprintf("The custom rule '%s' has an invalid pattern: %s", r.ID, err.Error()))
+	}
+	r.regex = regex
+	return nil
+}
+
+// Returns true if the rule checks the values of the base resources (`base` is true) or of the translations.
+func (r *CustomRule) appliesTo(base bool) bool {
+	switch r.Scope {
+	case ScopeBase:
+		return base
+	case ScopeTranslation:
+		return !base
+	}
+	return true
+}
+
+// Compiles the custom rules of the configuration and of its profiles.
+func (c *Config) compileCustomRules() error {
+	for _, rule := range c.CustomRules {
+		if err := rule.compile(); err != nil {
+			return err
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		if err := profile.compileCustomRules(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validates the values in `res` with the enabled custom rules of the `config`.
+// `base` is true if `res` are the base resources.
+func validateCustomRules(res *resources.Resources, shortPath string, config *Config, base bool) []error {
+	if config == nil {
+		return nil
+	}
+	var rules []*CustomRule
+	for _, rule := range config.CustomRules {
+		if rule.regex != nil && rule.appliesTo(base) && config.IsRuleEnabled(rule.ID) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var errorList []error
+	validateValue := func(name, value string) {
+		for _, rule := range rules {
+			if !rule.regex.MatchString(value) {
+				continue
+			}
+			message := rule.Message
+			if len(message) == 0 {
+				message = fmt.Sprintf("The value matches the pattern '%s'", rule.Pattern)
+			}
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: %s", name, shortPath, message), shortPath, name, rule.ID, nil, ""})
+		}
+	}
+	for _, el := range res.Strings {
+		validateValue(el.Name, el.Value)
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			validateValue(el.Name, item.Value)
+		}
+	}
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			validateValue(el.Name, item.Value)
+		}
+	}
+	return errorList
+}
@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Validates that the strings file at `path` does not declare the same string, plurals or string-array more than once.
+// The aapt merges such declarations silently (the last one wins). The files of a file set (see `resources.IsFileSet`)
+// are validated one by one; the declarations in the different files are reported by `validateCrossFileDuplicates`.
+func validateDuplicateNames(path, shortPath string) []error {
+	if resources.IsFileSet(filepath.Base(path)) {
+		paths, err := resources.ExpandPath(path)
+		if err != nil {
+			return []error{err}
+		}
+		var errorList []error
+		for _, p := range paths {
+			errorList = append(errorList, validateDuplicateNames(p, filepath.Join(filepath.Dir(shortPath), filepath.Base(p)))...)
+		}
+		return errorList
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	declarations, err := resources.ParseDeclarations(data)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errorList []error
+	lines := make(map[string][]string)
+	var order []string
+	for _, d := range declarations {
+		id := d.Element + "/" + d.Name
+		if _, ok := lines[id]; !ok {
+			order = append(order, id)
+		}
+		lines[id] = append(lines[id], fmt.Sprint(d.Line))
+	}
+	for _, id := range order {
+		if len(lines[id]) < 2 {
+			continue
+		}
+		parts := strings.SplitN(id, "/", 2)
+		element, name := parts[0], parts[1]
+		errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The <%s> is declared %d times, at lines %s; the last declaration wins", name, shortPath, element, len(lines[id]), strings.Join(lines[id], ", ")), shortPath, name, RuleDuplicateName, nil, ""})
+	}
+	return errorList
+}
+
+// A location of a resource declaration.
+type declarationSite struct {
+	shortPath string
+	line      int
+}
+
+// Validates that the XML files in each "values*" directory of the `resDir` do not declare the same resource,
+// e.g. a string declared in both "strings.xml" and "legacy_strings.xml".
+// The directories of the locales not included in the `config` are skipped.
+func validateCrossFileDuplicates(resDir string, config *Config) []error {
+	dirs, err := filepath.Glob(filepath.Join(resDir, "values*"))
+	if err != nil {
+		return []error{err}
+	}
+	sort.Strings(dirs)
+
+	var errorList []error
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+		if err != nil {
+			return []error{err}
+		}
+		if len(paths) < 2 || !config.IsLocaleIncluded(resources.ShortPath(resDir, paths[0])) {
+			continue
+		}
+		sort.Strings(paths)
+
+		sites := make(map[string][]declarationSite)
+		var order []string
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				errorList = append(errorList, err)
+				continue
+			}
+			declarations, err := resources.ParseDeclarations(data)
+			if err != nil {
+				errorList = append(errorList, errors.New(fmt.Sprintf("%s: %s", path, err.Error())))
+				continue
+			}
+			shortPath := resources.ShortPath(resDir, path)
+			for _, d := range declarations {
+				id := d.Element + "/" + d.Name
+				if _, ok := sites[id]; !ok {
+					order = append(order, id)
+				}
+				sites[id] = append(sites[id], declarationSite{shortPath, d.Line})
+			}
+		}
+
+		for _, id := range order {
+			declaredIn := sites[id]
+			var locations []string
+			files := make(map[string]bool)
+			for _, site := range declaredIn {
+				files[site.shortPath] = true
+				locations = append(locations, fmt.Sprintf("%s:%d", site.shortPath, site.line))
+			}
+			if len(files) < 2 {
+				continue
+			}
+			parts := strings.SplitN(id, "/", 2)
+			element, name := parts[0], parts[1]
+			last := declaredIn[len(declaredIn)-1].shortPath
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The <%s> is declared in multiple files, at %s", name, last, element, strings.Join(locations, ", ")), last, name, RuleDuplicateName, nil, ""})
+		}
+	}
+	return errorList
+}
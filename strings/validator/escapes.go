@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The escape sequences whose counts should be the same in the base and the translation,
+// since they are usually significant for the layout.
+var layoutEscapes = []byte{'n', 't'}
+
+// Returns the number of the `\n` and `\t` escape sequences in the value; an escaped backslash (`\\n`) is not counted.
+func countEscapes(value string) map[byte]int {
+	counts := make(map[byte]int)
+	for i := 0; i < len(value)-1; i++ {
+		if value[i] != '\\' {
+			continue
+		}
+		counts[value[i+1]] += 1
+		i++
+	}
+	return counts
+}
+
+// Validates that the `validatedElemString` has as many `\n` and `\t` escape sequences as the `baseElemString`.
+func validateEscapeParity(baseElemString, validatedElemString string) error {
+	baseCounts, targetCounts := countEscapes(baseElemString), countEscapes(validatedElemString)
+	var problems []string
+	for _, escape := range layoutEscapes {
+		if baseCounts[escape] != targetCounts[escape] {
+			problems = append(problems, fmt.Sprintf("%d '\\%c' (the base string has %d)", targetCounts[escape], escape, baseCounts[escape]))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("The target string has %s", strings.Join(problems, " and ")))
+	}
+	return nil
+}
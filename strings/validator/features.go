@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/usage"
+	"path/filepath"
+)
+
+// Validates string resource isolation between the base app module and dynamic feature modules.
+// `baseModuleDir` and `featureModuleDirs` are paths to Gradle module directories (containing "src/main/res").
+// Reports strings referenced from a feature module but defined only in the base module,
+// and strings referenced from the base module but defined only in a feature module.
+// Such references may cause a Resources.NotFoundException when a module is delivered on demand.
+func ValidateFeatureIsolation(baseModuleDir string, featureModuleDirs []string, baseLocale, stringsFilename string) (errorList []error) {
+	errorList = make([]error, 0)
+	baseNames, baseReferences, err := loadModuleStrings(baseModuleDir, baseLocale, stringsFilename)
+	if err != nil {
+		errorList = append(errorList, err)
+		return
+	}
+
+	for _, featureDir := range featureModuleDirs {
+		featureNames, featureReferences, err := loadModuleStrings(featureDir, baseLocale, stringsFilename)
+		if err != nil {
+			errorList = append(errorList, err)
+			continue
+		}
+
+		for name, refs := range featureReferences {
+			if !featureNames[name] && baseNames[name] {
+				for _, ref := range refs {
+					errorList = append(errorList, &ValidationError{fmt.Sprintf("%s is referenced from the feature module %s (%s:%d), but defined only in the base module", name, featureDir, ref.Path, ref.Line), ref.Path, name, RuleFeatureIsolation, nil, ""})
+				}
+			}
+		}
+		for name, refs := range baseReferences {
+			if !baseNames[name] && featureNames[name] {
+				for _, ref := range refs {
+					errorList = append(errorList, &ValidationError{fmt.Sprintf("%s is referenced from the base module (%s:%d), but defined only in the feature module %s", name, ref.Path, ref.Line, featureDir), ref.Path, name, RuleFeatureIsolation, nil, ""})
+				}
+			}
+		}
+	}
+
+	sortErrors(errorList)
+	return
+}
+
+// Returns the set of string names defined in the module at `moduleDir`,
+// and the string references found in the module's sources.
+func loadModuleStrings(moduleDir, baseLocale, stringsFilename string) (map[string]bool, map[string][]usage.Reference, error) {
+	srcDir := filepath.Join(moduleDir, "src")
+	moduleResources, err := resources.Parse(filepath.Join(srcDir, "main", "res"), baseLocale, stringsFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]bool)
+	for _, el := range moduleResources.Strings {
+		names[el.Name] = true
+	}
+	references, err := usage.FindStringReferences(srcDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return names, references, nil
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"encoding/json"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/resources"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// The source of the strings written by `FillSuggestions`, recorded in the audit log.
+const fillSource = "suggestions"
+
+// A suggested translation which needs a review before it is written to the strings files.
+type ReviewEntry struct {
+	// The path of the strings file missing the translation; the short path (e.g. "values-de/strings.xml") in a `FillReport`.
+	Path string
+	// The name of the missing string.
+	Key string
+	// The suggested translation.
+	Translation string
+	// The name of the string the translation is taken from.
+	From string
+	// The confidence (0-1) in the suggestion.
+	Confidence float64
+	// The reasons for the review, e.g. "placeholders differ" (see `Suggestion.ReviewReasons`).
+	Reasons []string
+}
+
+// The outcome of `FillSuggestions`.
+type FillReport struct {
+	// The number of the approved translations written to each strings file, keyed by its short path.
+	Written map[string]int
+	// The suggestions needing a review, which are not written to the strings files.
+	NeedsReview []ReviewEntry
+	// The number of the suggestions needing a review for each reason; a suggestion may have several reasons.
+	Reasons map[string]int
+	// The number of the approved translations not written, since their strings file does not exist.
+	Skipped int
+}
+
+// Returns the total number of the translations written to the strings files.
+func (r *FillReport) WrittenCount() int {
+	count := 0
+	for _, c := range r.Written {
+		count += c
+	}
+	return count
+}
+
+// Writes the approved suggestions of the missing strings in `errorList` (see `Options.Suggest`)
+// as final translations into the existing strings files of the `resDir`.
+// The suggestions needing a review are only listed in the report, e.g. to be written with `WriteReviewFile`.
+func FillSuggestions(resDir string, errorList []error) (*FillReport, error) {
+	report := &FillReport{Written: make(map[string]int), Reasons: make(map[string]int)}
+	approved := make(map[string][]resources.String)
+	var paths []string
+	for _, err := range errorList {
+		me, ok := err.(*ResourceMissingError)
+		if !ok || me.Suggestion == nil {
+			continue
+		}
+		s := me.Suggestion
+		if s.NeedsReview {
+			reasons := s.ReviewReasons()
+			for _, reason := range reasons {
+				report.Reasons[reason] += 1
+			}
+			report.NeedsReview = append(report.NeedsReview, ReviewEntry{me.Path, me.Key, s.Translation, s.Key, s.Confidence, reasons})
+			continue
+		}
+		if _, ok := approved[me.Path]; !ok {
+			paths = append(paths, me.Path)
+		}
+		value := resources.EscapeValue(resources.UnescapeValue(s.Translation))
+		approved[me.Path] = append(approved[me.Path], resources.String{Name: me.Key, RawValue: value})
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		filePath := filepath.Join(resDir, path)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			report.Skipped += len(approved[path])
+			continue
+		}
+		if err := resources.AppendStrings(filePath, approved[path], fillSource); err != nil {
+			return report, err
+		}
+		report.Written[path] = len(approved[path])
+	}
+	return report, nil
+}
+
+// Writes the `entries` needing a review to the JSON file at `path`.
+func WriteReviewFile(path string, entries []ReviewEntry) error {
+	if entries == nil {
+		entries = []ReviewEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return audit.WriteFile(path, append(data, '\n'), fillSource)
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFillSuggestions(t *testing.T) {
+	resDir := t.TempDir()
+	write := func(dir, content string) {
+		if err := os.MkdirAll(filepath.Join(resDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(resDir, dir, "strings.xml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("values", `<resources>
+    <string name="hello_user">Hello %s!</string>
+    <string name="hello_user_again">Hello %s!</string>
+    <string name="hi_user">Hello %s</string>
+    <string name="items">%d items in the cart</string>
+    <string name="item_count">%s items in the cart</string>
+</resources>
+`)
+	write("values-de", `<resources>
+    <string name="hello_user">Hallo %s, "du"!</string>
+    <string name="items">%d Artikel im Warenkorb</string>
+</resources>
+`)
+	errorList := Validate(resDir, "", "strings.xml", Options{ShowMissing: true, Suggest: true})
+	report, err := FillSuggestions(resDir, errorList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]int{"values-de/strings.xml": 1}; !reflect.DeepEqual(report.Written, want) {
+		t.Errorf("got the written translations %v, want %v", report.Written, want)
+	}
+	var reviewed []string
+	for _, entry := range report.NeedsReview {
+		reviewed = append(reviewed, entry.Key)
+	}
+	if want := []string{"hi_user", "item_count"}; !reflect.DeepEqual(reviewed, want) {
+		t.Errorf("got the translations needing review %v, want %v", reviewed, want)
+	}
+	if want := map[string]int{ReviewReasonSimilarity: 1, ReviewReasonPlaceholders: 1}; !reflect.DeepEqual(report.Reasons, want) {
+		t.Errorf("got the review reasons %v, want %v", report.Reasons, want)
+	}
+
+	filled, err := resources.ParseFile(filepath.Join(resDir, "values-de", "strings.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := filled.FindString("hello_user_again")
+	if written == nil {
+		t.Fatal("the approved translation is not written")
+	}
+	// the text compiled by aapt is kept
+	if got, want := resources.UnescapeValue(written.Value), resources.UnescapeValue(filled.FindString("hello_user").Value); got != want {
+		t.Errorf("got the written text %q, want %q", got, want)
+	}
+	for _, key := range reviewed {
+		if filled.FindString(key) != nil {
+			t.Errorf("the translation of %s needing review is written", key)
+		}
+	}
+}
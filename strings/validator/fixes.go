@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches a newline with the indentation around it.
+var newlineFixRegex *regexp.Regexp = regexp.MustCompile("[ \\t]*\\n[ \\t]*")
+
+// Returns the suggested replacement of the `rawValue` for an error of the `rule`, or an empty string
+// if the rule has no mechanical fix. The `baseRawValue` is the raw value of the corresponding base string.
+func suggestFix(rule, baseRawValue, rawValue string) string {
+	var fix string
+	switch rule {
+	case RuleUnescapedQuotes:
+		fix = escapeQuotes(rawValue)
+	case RuleNewline:
+		fix = newlineFixRegex.ReplaceAllString(rawValue, " ")
+	case RuleTabCharacters:
+		fix = strings.Replace(rawValue, "\t", " ", -1)
+	case RuleIOSSpecifiers:
+		fix = replaceIOSSpecifiers(rawValue)
+	case RulePositionalPlaceholders:
+		fix = withMissingPlaceholders(baseRawValue, rawValue)
+	default:
+		return ""
+	}
+	if fix == rawValue {
+		return ""
+	}
+	return fix
+}
+
+// Returns the `rawValue` with the apostrophes outside the double-quoted parts escaped, and with the unpaired
+// double quote escaped (see `validateQuotesEscaping`). The markup tags are left as they are.
+func escapeQuotes(rawValue string) string {
+	var fixed strings.Builder
+	escaped := false
+	inTag := false
+	inQuotes := false
+	// the position of the last opening double quote in the `fixed` value
+	quotePosition := 0
+	for _, r := range rawValue {
+		switch {
+		case inTag:
+			inTag = r != '>'
+		case escaped:
+			escaped = false
+		case r == '<':
+			inTag = true
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			quotePosition = fixed.Len()
+		case r == '\'' && !inQuotes:
+			fixed.WriteRune('\\')
+		}
+		fixed.WriteRune(r)
+	}
+	if inQuotes {
+		s := fixed.String()
+		return s[:quotePosition] + "\\" + s[quotePosition:]
+	}
+	return fixed.String()
+}
+
+// Returns the `rawValue` with the positional placeholders of the `baseRawValue` for the arguments
+// it does not format appended, e.g. "Hello" with "%1$s" for the base "Hello %1$s".
+func withMissingPlaceholders(baseRawValue, rawValue string) string {
+	formatted := make(map[string]bool)
+	for _, match := range PositionalPlaceholderRegex.FindAllString(withoutEscapedPercents(rawValue), -1) {
+		index, _ := splitPositionalPlaceholder(match)
+		formatted[index] = true
+	}
+	fixed := rawValue
+	for _, match := range PositionalPlaceholderRegex.FindAllString(withoutEscapedPercents(baseRawValue), -1) {
+		if index, _ := splitPositionalPlaceholder(match); !formatted[index] {
+			formatted[index] = true
+			fixed += " " + match
+		}
+	}
+	return fixed
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/glossary"
+	"strings"
+)
+
+// Returns a validation function checking that the translation uses the approved translations (for the `locale`)
+// of the glossary terms found in the base string.
+func glossaryValidation(g glossary.Glossary, locale string) comparisonValidation {
+	return func(baseElemString, validatedElemString string) error {
+		var problems []string
+		for _, term := range g.TermsIn(baseElemString) {
+			approved := g.Approved(term, locale)
+			if len(approved) == 0 || glossary.Uses(validatedElemString, approved) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("'%s' should be translated as '%s'", term, strings.Join(approved, "' or '")))
+		}
+		if len(problems) > 0 {
+			return errors.New(fmt.Sprintf("The target string does not use the approved terminology: %s", strings.Join(problems, ", ")))
+		}
+		return nil
+	}
+}
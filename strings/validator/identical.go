@@ -0,0 +1,21 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Returns a validation function reporting the translations identical to the base value,
+// except for the `allowed` values (e.g. brand names) and the values without letters (e.g. "%1$d").
+func identicalValidation(allowed []string) comparisonValidation {
+	return func(baseElemString, validatedElemString string) error {
+		if baseElemString != validatedElemString || containsString(allowed, strings.TrimSpace(validatedElemString)) {
+			return nil
+		}
+		if strings.IndexFunc(SimplePlaceholderRegex.ReplaceAllString(PositionalPlaceholderRegex.ReplaceAllString(validatedElemString, ""), ""), unicode.IsLetter) < 0 {
+			return nil
+		}
+		return errors.New("The target string is identical to the base string, it is probably not translated")
+	}
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Reads the names listed in the `IgnoreFile` of the configuration and of its profiles, and adds them to their `Ignore`.
+func (c *Config) loadIgnoreFiles() error {
+	if len(c.IgnoreFile) > 0 {
+		file, err := os.Open(c.IgnoreFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if name := strings.TrimSpace(scanner.Text()); len(name) > 0 && !strings.HasPrefix(name, "#") {
+				c.Ignore = append(c.Ignore, name)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		if err := profile.loadIgnoreFiles(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the `errorList` without the errors of the resources ignored in the `config` (see `Config.Ignore`).
+func withoutIgnoredNames(errorList []error, config *Config) []error {
+	if config == nil || len(config.Ignore) == 0 {
+		return errorList
+	}
+	var filtered []error
+	for _, e := range errorList {
+		_, key, rule := errorLocation(e)
+		if len(rule) == 0 || !matchesAnyPattern(key, config.Ignore) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+)
+
+// Returns the problem with the indexing of the format placeholders of the `value`, or an empty string if there is none:
+// the unindexed placeholders (e.g. %s) mixed with the positional ones (e.g. %1$s), or more than one unindexed placeholder,
+// whose arguments cannot be reordered in a translation.
+func placeholderIndexingProblem(value string) string {
+	unindexed, positional := 0, 0
+	for _, match := range FormatSpecifierRegex.FindAllStringSubmatch(withoutEscapedPercents(value), -1) {
+		if strings.HasSuffix(match[0], "n") || strings.Contains(match[0], "<") {
+			// the line separator (%n) and the reused previous argument (e.g. %<s) do not take an argument
+			continue
+		}
+		if len(match[1]) > 0 {
+			positional += 1
+		} else {
+			unindexed += 1
+		}
+	}
+	if unindexed > 0 && positional > 0 {
+		return "mixes the unindexed (e.g. %s) and the positional (e.g. %1$s) placeholders"
+	}
+	if unindexed > 1 {
+		return fmt.Sprintf("has %d unindexed placeholders, whose order cannot be changed in the translations; use the positional ones (e.g. %%1$s and %%2$s)", unindexed)
+	}
+	return ""
+}
+
+// Validates that the strings, plurals and string arrays in `res` do not mix the unindexed and the positional placeholders,
+// and do not have several unindexed ones. The base strings are validated as well, since a translation
+// reordering the arguments of the unindexed placeholders silently formats them in the wrong order.
+func validatePlaceholderIndexing(res *resources.Resources, shortPath string) []error {
+	var errorList []error
+	indexingError := func(name, label, value, problem string) error {
+		return &ValidationError{fmt.Sprintf("%s in %s: The value '%s' %s", label, shortPath, NewLineRegex.ReplaceAllString(value, "\\n"), problem), shortPath, name, RulePlaceholderIndexing, nil, ""}
+	}
+	for _, el := range res.Strings {
+		if !el.IsFormatted() {
+			continue
+		}
+		if problem := placeholderIndexingProblem(el.Value); len(problem) > 0 {
+			errorList = append(errorList, indexingError(el.Name, el.Name, el.Value, problem))
+		}
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			if problem := placeholderIndexingProblem(item.Value); len(problem) > 0 {
+				errorList = append(errorList, indexingError(el.Name, fmt.Sprintf("%s (%s)", el.Name, item.Quantity), item.Value, problem))
+				break
+			}
+		}
+	}
+	for _, el := range res.StringArrays {
+		for i, item := range el.Items {
+			if problem := placeholderIndexingProblem(item.Value); len(problem) > 0 {
+				errorList = append(errorList, indexingError(el.Name, fmt.Sprintf("%s (item %d)", el.Name, i+1), item.Value, problem))
+				break
+			}
+		}
+	}
+	return errorList
+}
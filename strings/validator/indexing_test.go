@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderIndexing(t *testing.T) {
+	tests := []struct {
+		name        string
+		resource    string
+		wantErrors  int
+		wantMessage string
+	}{
+		{"positional string", `<string name="key">%1$s and %2$s</string>`, 0, ""},
+		{"mixed string", `<string name="key">%1$s and %s</string>`, 1, "key in values/strings.xml: The value '%1$s and %s' mixes"},
+		{"not formatted string", `<string name="key" formatted="false">%s and %s</string>`, 0, ""},
+		{"string with a newline", "<string name=\"key\">%s\nand %s</string>", 1, "The value '%s\\nand %s' has 2 unindexed placeholders"},
+		{"plural", `<plurals name="key"><item quantity="one">%d of %s</item><item quantity="other">%d of %s</item></plurals>`, 1, "key (one) in values/strings.xml"},
+		{"string array", `<string-array name="key"><item>One</item><item>%s of %s</item><item>%s or %s</item></string-array>`, 1, "key (item 2) in values/strings.xml: The value '%s of %s'"},
+		{"positional string array", `<string-array name="key"><item>%1$s of %2$s</item></string-array>`, 0, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resDir := t.TempDir()
+			writeFile(t, filepath.Join(resDir, "values", "strings.xml"), "<resources>\n    "+test.resource+"\n</resources>\n")
+			errorList := Validate(resDir, "", "strings.xml", Options{})
+			found := errorsOf(errorList, "values/strings.xml", RulePlaceholderIndexing)
+			if len(found) != test.wantErrors {
+				t.Fatalf("got %d error(s), want %d: %v", len(found), test.wantErrors, found)
+			}
+			if len(found) > 0 && !strings.Contains(found[0].Error(), test.wantMessage) {
+				t.Errorf("got the message %q, want it to contain %q", found[0].Error(), test.wantMessage)
+			}
+		})
+	}
+}
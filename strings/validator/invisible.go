@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The names of the invisible characters, which are easy to paste by accident and hard to spot in the editors.
+var invisibleCharacters = map[rune]string{
+	'\u00a0': "NO-BREAK SPACE",
+	'\u00ad': "SOFT HYPHEN",
+	'\u180e': "MONGOLIAN VOWEL SEPARATOR",
+	'\u200b': "ZERO WIDTH SPACE",
+	'\u200c': "ZERO WIDTH NON-JOINER",
+	'\u200d': "ZERO WIDTH JOINER",
+	'\u2060': "WORD JOINER",
+	'\u2061': "FUNCTION APPLICATION",
+	'\u2062': "INVISIBLE TIMES",
+	'\u2063': "INVISIBLE SEPARATOR",
+	'\u2064': "INVISIBLE PLUS",
+	'\u202f': "NARROW NO-BREAK SPACE",
+	'\ufeff': "BYTE ORDER MARK",
+}
+
+// Returns true if the rune at `i` is a joiner (ZWJ or ZWNJ) between two non-ASCII characters,
+// where it shapes the text (e.g. in Persian, the Indic scripts or the emoji sequences).
+func isShapingJoiner(runes []rune, i int) bool {
+	if runes[i] != '\u200c' && runes[i] != '\u200d' {
+		return false
+	}
+	if i == 0 || i == len(runes)-1 {
+		return false
+	}
+	before, after := runes[i-1], runes[i+1]
+	return before > unicode.MaxASCII && after > unicode.MaxASCII && !unicode.IsSpace(before) && !unicode.IsSpace(after)
+}
+
+// Validates that the value does not have raw invisible characters (e.g. the zero width space or the byte order mark);
+// the intended ones should be written escaped (e.g. "\u00A0"), so they are visible in the editors.
+func validateInvisibleCharacters(elemValue string) error {
+	var found []string
+	runes := []rune(elemValue)
+	for i, r := range runes {
+		name, ok := invisibleCharacters[r]
+		if !ok || isShapingJoiner(runes, i) {
+			continue
+		}
+		found = append(found, fmt.Sprintf("%s (U+%04X) at position %d", name, r, i))
+	}
+	if len(found) > 0 {
+		return errors.New(fmt.Sprintf("The value has invisible character(s): %s; remove them or use the escaped form (e.g. \\u00A0)", strings.Join(found, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package validator
+
+// Returns the `errorList` without the errors of the resources whose names do not match any of the glob `patterns`
+// (e.g. "checkout_*"). The errors not referring to a resource (e.g. I/O errors) are kept.
+func withKeys(errorList []error, patterns []string) []error {
+	var filtered []error
+	for _, e := range errorList {
+		_, key, rule := errorLocation(e)
+		if len(rule) == 0 || matchesAnyPattern(key, patterns) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches the URLs, e.g. "https://example.com/help" or "www.example.com".
+var URLRegex *regexp.Regexp = regexp.MustCompile("(?i)\\b(?:[a-z][a-z0-9+.-]*://|www\\.)[^\\s\"'<>]+")
+
+// Matches the email addresses, e.g. "support@example.com".
+var EmailRegex *regexp.Regexp = regexp.MustCompile("[A-Za-z0-9._+-]+@[A-Za-z0-9-]+(?:\\.[A-Za-z0-9-]+)*\\.[A-Za-z]{2,}")
+
+// Returns the URLs and the email addresses in `s`, without the punctuation following them.
+func linksIn(s string) map[string]bool {
+	links := make(map[string]bool)
+	for _, url := range URLRegex.FindAllString(s, -1) {
+		links[strings.TrimRight(url, ".,;:!?)")] = true
+	}
+	for _, email := range EmailRegex.FindAllString(s, -1) {
+		links[email] = true
+	}
+	return links
+}
+
+// Returns the sorted keys of `links` that are not in `other`.
+func linksNotIn(links, other map[string]bool) []string {
+	var result []string
+	for link := range links {
+		if !other[link] {
+			result = append(result, link)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Validates that the `validatedElemString` has the same URLs and email addresses as the `baseElemString`, unaltered.
+func validateLinks(baseElemString, validatedElemString string) error {
+	baseLinks, targetLinks := linksIn(baseElemString), linksIn(validatedElemString)
+	var problems []string
+	if missing := linksNotIn(baseLinks, targetLinks); len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("is missing the URL(s) or email(s): %s", strings.Join(missing, ", ")))
+	}
+	if extra := linksNotIn(targetLinks, baseLinks); len(extra) > 0 {
+		problems = append(problems, fmt.Sprintf("has URL(s) or email(s) not in the base string: %s", strings.Join(extra, ", ")))
+	}
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("The target string %s", strings.Join(problems, " and ")))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Validates the qualifiers of the "values-*" directories of the `resDir`, e.g. reports "values-en-US",
+// which aapt does not recognize as a locale ("values-en-rUS" is expected).
+func validateLocaleDirectories(resDir string) []error {
+	dirs, err := filepath.Glob(filepath.Join(resDir, "values-*"))
+	if err != nil {
+		return []error{err}
+	}
+	sort.Strings(dirs)
+
+	var errorList []error
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		name := filepath.Base(dir)
+		if _, err := resources.ParseQualifiers(name); err != nil {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s: Invalid resource directory name: %s", name, err.Error()), name, "", RuleLocaleDirectory, nil, ""})
+		}
+	}
+	return errorList
+}
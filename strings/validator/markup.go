@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches opening, closing and self-closing markup tags, e.g. <b>, </b>, <br/> or <a href="...">.
+var MarkupTagRegex *regexp.Regexp = regexp.MustCompile("<(/?)([a-zA-Z][a-zA-Z0-9:]*)[^<>]*?(/?)>")
+
+// Tags that never have a closing counterpart.
+var voidMarkupTags = map[string]bool{"br": true, "img": true, "hr": true}
+
+type markupTag struct {
+	name    string
+	closing bool
+	void    bool
+}
+
+// Returns the markup of the raw (inner XML) value: the XML elements are kept as tags,
+// the text is XML-decoded (so that &lt;b> becomes <b>) and the CDATA sections are kept verbatim.
+func markupText(rawValue string) string {
+	var text strings.Builder
+	err := resources.DecodeRawValue(rawValue, func(token xml.Token) {
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if len(t.Name.Space) > 0 {
+				name = t.Name.Space + ":" + name
+			}
+			text.WriteString("<" + name + ">")
+		case xml.EndElement:
+			name := t.Name.Local
+			if len(t.Name.Space) > 0 {
+				name = t.Name.Space + ":" + name
+			}
+			text.WriteString("</" + name + ">")
+		case xml.CharData:
+			text.Write(t)
+		}
+	})
+	if err != nil {
+		return html.UnescapeString(rawValue)
+	}
+	return text.String()
+}
+
+// Finds the markup tags in the raw (inner XML) value of a string.
+// Literal tags (<b>), escaped tags (&lt;b>) and tags inside CDATA sections are recognized.
+// Namespaced tags (e.g. xliff:g) are not a markup and are skipped.
+func parseMarkupTags(rawValue string) []markupTag {
+	var tags []markupTag
+	for _, match := range MarkupTagRegex.FindAllStringSubmatch(markupText(rawValue), -1) {
+		name := strings.ToLower(match[2])
+		if strings.Contains(name, ":") {
+			continue
+		}
+		tags = append(tags, markupTag{name, match[1] == "/", match[3] == "/" || voidMarkupTags[name]})
+	}
+	return tags
+}
+
+// Returns an error if the tags are not properly nested or closed.
+func validateMarkupBalance(tags []markupTag) error {
+	var stack []string
+	for _, tag := range tags {
+		if tag.void {
+			continue
+		}
+		if !tag.closing {
+			stack = append(stack, tag.name)
+			continue
+		}
+		if len(stack) == 0 {
+			return errors.New(fmt.Sprintf("The target string has a closing tag </%s> without an opening tag", tag.name))
+		}
+		if top := stack[len(stack)-1]; top != tag.name {
+			return errors.New(fmt.Sprintf("The target string has a mismatched closing tag </%s>, while it should be </%s>", tag.name, top))
+		}
+		stack = stack[:len(stack)-1]
+	}
+	if len(stack) > 0 {
+		return errors.New(fmt.Sprintf("The target string has an unclosed tag <%s>", stack[len(stack)-1]))
+	}
+	return nil
+}
+
+func countOpeningTags(tags []markupTag) map[string]int {
+	counts := make(map[string]int)
+	for _, tag := range tags {
+		if !tag.closing {
+			counts[tag.name] += 1
+		}
+	}
+	return counts
+}
+
+// Validates that the `validatedElemString` has the same markup tags as the `baseElemString`,
+// and that the tags are balanced. Both arguments are expected to be raw (inner XML) values.
+func validateMarkup(baseElemString, validatedElemString string) error {
+	baseTags := parseMarkupTags(baseElemString)
+	targetTags := parseMarkupTags(validatedElemString)
+	if len(baseTags) == 0 && len(targetTags) == 0 {
+		return nil
+	}
+	if validateMarkupBalance(baseTags) == nil {
+		if err := validateMarkupBalance(targetTags); err != nil {
+			return err
+		}
+	}
+
+	baseCounts := countOpeningTags(baseTags)
+	targetCounts := countOpeningTags(targetTags)
+	var missing, extra []string
+	for name, count := range baseCounts {
+		if targetCounts[name] < count {
+			missing = append(missing, fmt.Sprintf("<%s>", name))
+		}
+	}
+	for name, count := range targetCounts {
+		if baseCounts[name] < count {
+			extra = append(extra, fmt.Sprintf("<%s>", name))
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	if len(missing) > 0 && len(extra) > 0 {
+		return errors.New(fmt.Sprintf("The target string is missing the %s tag(s) and has extra %s tag(s)", strings.Join(missing, ", "), strings.Join(extra, ", ")))
+	} else if len(missing) > 0 {
+		return errors.New(fmt.Sprintf("The target string is missing the %s tag(s)", strings.Join(missing, ", ")))
+	} else if len(extra) > 0 {
+		return errors.New(fmt.Sprintf("The target string has extra %s tag(s)", strings.Join(extra, ", ")))
+	}
+	return nil
+}
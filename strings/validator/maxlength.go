@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// Returns the maximal length of the string `name`: the one declared in the `MaxLengths` of the configuration
+// (by the name or a glob pattern like "notification_*"), or with the maxLength=N comment in the `baseResources`.
+// Returns zero if there is no limit.
+func (c *Config) maxLengthOf(name string, baseResources *resources.Resources) int {
+	if c != nil {
+		if n, ok := c.MaxLengths[name]; ok {
+			return n
+		}
+		for pattern, n := range c.MaxLengths {
+			if matched, _ := filepath.Match(pattern, name); matched {
+				return n
+			}
+		}
+	}
+	return baseResources.MaxLength(name)
+}
+
+// Validates that the strings and plurals in `res` are not longer than their maximal lengths (see `Config.maxLengthOf`).
+// The length is counted in characters of the text shown to the user, i.e. after resolving the escape sequences.
+func validateMaxLength(res, baseResources *resources.Resources, shortPath string, config *Config) []error {
+	var errorList []error
+	check := func(name, label, value string) {
+		maxLength := config.maxLengthOf(name, baseResources)
+		if maxLength <= 0 {
+			return
+		}
+		if length := len([]rune(resources.UnescapeValue(value))); length > maxLength {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The value '%s' has %d characters, more than the maximum of %d", label, shortPath, value, length, maxLength), shortPath, name, RuleMaxLength, nil, ""})
+		}
+	}
+	for _, el := range res.Strings {
+		check(el.Name, el.Name, el.Value)
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			check(el.Name, fmt.Sprintf("%s (%s)", el.Name, item.Quantity), item.Value)
+		}
+	}
+	return errorList
+}
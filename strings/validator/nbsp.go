@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The policies of the "non-breaking-space" rule (see `Config.NonBreakingSpace`).
+const (
+	// The non-breaking spaces are reported wherever they are used.
+	NonBreakingSpaceForbid = "forbid"
+	// A non-breaking space is required before the "!", "?", ":" and ";" punctuation (e.g. in French),
+	// i.e. a regular space before it is reported.
+	NonBreakingSpaceRequire = "require"
+)
+
+// Matches a non-breaking space (U+00A0) or a narrow one (U+202F), raw or escaped (e.g. "\u00A0").
+var nonBreakingSpaceRegex *regexp.Regexp = regexp.MustCompile("[\u00a0\u202f]|\\\\u(00[aA]0|202[fF])")
+
+// Matches a regular space before the punctuation which needs a non-breaking space.
+var spaceBeforePunctuationRegex *regexp.Regexp = regexp.MustCompile(" +[!?:;]")
+
+// Returns the non-breaking space policy of the `locale`, configured by the locale, the language or "*",
+// or an empty string if there is none.
+func (c *Config) nonBreakingSpaceFor(locale string) string {
+	if c == nil {
+		return ""
+	}
+	for _, key := range []string{locale, languageOf(locale), "*"} {
+		if policy, ok := c.NonBreakingSpace[key]; ok {
+			return policy
+		}
+	}
+	return ""
+}
+
+// Returns an error if any of the policies of the non-breaking spaces is unknown.
+func (c *Config) checkNonBreakingSpace() error {
+	for locale, policy := range c.NonBreakingSpace {
+		if policy != NonBreakingSpaceForbid && policy != NonBreakingSpaceRequire {
+			return errors.New(fmt.Sprintf("The non-breaking space policy of '%s' is '%s', but it should be '%s' or '%s'.", locale, policy, NonBreakingSpaceForbid, NonBreakingSpaceRequire))
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		if err := profile.checkNonBreakingSpace(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns a validation function enforcing the non-breaking space `policy`.
+func nonBreakingSpaceValidation(policy string) simpleValidation {
+	return func(elemValue string) error {
+		var matches [][]int
+		var problem string
+		if policy == NonBreakingSpaceForbid {
+			matches = nonBreakingSpaceRegex.FindAllStringIndex(elemValue, -1)
+			problem = "has non-breaking space(s)"
+		} else {
+			matches = spaceBeforePunctuationRegex.FindAllStringIndex(elemValue, -1)
+			problem = "has a regular space instead of a non-breaking one (\\u00A0) before the punctuation"
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		var positions []string
+		for _, match := range matches {
+			positions = append(positions, fmt.Sprint(len([]rune(elemValue[:match[0]]))))
+		}
+		return errors.New(fmt.Sprintf("Value '%s' %s at position(s) %s", NewLineRegex.ReplaceAllString(elemValue, "\\n"), problem, strings.Join(positions, ", ")))
+	}
+}
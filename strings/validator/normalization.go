@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/resources"
+	"golang.org/x/text/unicode/norm"
+	"io/ioutil"
+)
+
+// Validates that the values in `res` are in the Unicode Normalization Form C, so that the same text
+// has the same bytes in every file (e.g. "é" is not written as "e" followed by the combining acute accent).
+func validateNormalization(res *resources.Resources, shortPath string) []error {
+	var errorList []error
+	check := func(name, value string) bool {
+		if norm.NFC.IsNormalString(value) {
+			return true
+		}
+		errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The value '%s' is not in the Unicode NFC form; run with -fix to normalize it", name, shortPath, value), shortPath, name, RuleNormalization, nil, ""})
+		return false
+	}
+	for _, el := range res.Strings {
+		check(el.Name, el.Value)
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			if !check(el.Name, item.Value) {
+				break
+			}
+		}
+	}
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			if !check(el.Name, item.Value) {
+				break
+			}
+		}
+	}
+	return errorList
+}
+
+// Converts the strings file at `path` to the Unicode Normalization Form C.
+// Returns true if the file was changed.
+func FixNormalization(path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if norm.NFC.IsNormal(data) {
+		return false, nil
+	}
+	return true, audit.WriteFile(path, norm.NFC.Bytes(data), "fix:"+RuleNormalization)
+}
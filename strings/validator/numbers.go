@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Matches the numbers, including the ones with the thousands and decimal separators (e.g. "1,000", "1 000" or "2.5").
+var numberRegex *regexp.Regexp = regexp.MustCompile("\\p{Nd}+(?:[.,\u00a0\u202f ]\\p{Nd}{3})*(?:[.,]\\p{Nd}+)?")
+
+// Returns the value of the decimal digit `r` of any script (e.g. 3 for '3' or '٣').
+func digitValue(r rune) rune {
+	zero := r
+	for unicode.IsDigit(zero - 1) {
+		zero--
+	}
+	return (r - zero) % 10
+}
+
+// Returns the numbers in the text of `s` (ignoring the format specifiers, URLs and email addresses) as ASCII digits
+// without the separators, so that "1,000" and "1.000" are the same number.
+func numbersIn(s string) []string {
+	s = FormatSpecifierRegex.ReplaceAllString(withoutEscapedPercents(s), " ")
+	s = URLRegex.ReplaceAllString(s, " ")
+	s = EmailRegex.ReplaceAllString(s, " ")
+	var numbers []string
+	for _, match := range numberRegex.FindAllString(s, -1) {
+		var digits strings.Builder
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits.WriteRune('0' + digitValue(r))
+			}
+		}
+		numbers = append(numbers, digits.String())
+	}
+	sort.Strings(numbers)
+	return numbers
+}
+
+// Returns the numbers in `numbers` which are not in `other`, respecting the number of occurrences.
+func numbersNotIn(numbers, other []string) []string {
+	counts := make(map[string]int)
+	for _, n := range other {
+		counts[n] += 1
+	}
+	var result []string
+	for _, n := range numbers {
+		if counts[n] > 0 {
+			counts[n] -= 1
+		} else {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// Validates that the `validatedElemString` has the same numbers as the `baseElemString` (e.g. "30 days" or "24/7").
+func validateNumbers(baseElemString, validatedElemString string) error {
+	baseNumbers, targetNumbers := numbersIn(baseElemString), numbersIn(validatedElemString)
+	var problems []string
+	if missing := numbersNotIn(baseNumbers, targetNumbers); len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("is missing the number(s): %s", strings.Join(missing, ", ")))
+	}
+	if extra := numbersNotIn(targetNumbers, baseNumbers); len(extra) > 0 {
+		problems = append(problems, fmt.Sprintf("has number(s) not in the base string: %s", strings.Join(extra, ", ")))
+	}
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("The target string %s", strings.Join(problems, " and ")))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The resources overriding the strings for a configuration, e.g. "values-watch" and "values-de-watch",
+// "values-night" and "values-de-night", or the values directories of a separate overlay module
+// (e.g. for Android Automotive).
+type overlay struct {
+	// The configuration qualifiers of the overlay directories other than the locale (e.g. "watch" or "night" and "v21").
+	config []string
+	// Describes the overlay in the messages, e.g. "the watch form factor".
+	description string
+	// The res directory of the overlay module, or empty for the overlays next to the base resources.
+	moduleDir string
+	// The paths of the overlay files, keyed by their locale; the base overlay has an empty locale.
+	paths map[string]string
+}
+
+// Returns the overlay of the values directories with the configuration qualifiers `config`.
+func newOverlay(config []string) *overlay {
+	name := strings.Join(config, "-")
+	description := fmt.Sprintf("the %s configuration", name)
+	if _, formFactor := resources.FormFactorFromPath(filepath.Join(resources.ValuesDir(name), "strings.xml")); formFactor == name {
+		description = fmt.Sprintf("the %s form factor", name)
+	}
+	return &overlay{config, description, "", make(map[string]string)}
+}
+
+// Returns the path of the overlay file for the `locale`, in the `resDir` of the base resources.
+func (o *overlay) pathFor(resDir, locale, stringsFilename string) string {
+	if len(o.moduleDir) > 0 {
+		return filepath.Join(o.moduleDir, resources.ValuesDir(locale), stringsFilename)
+	}
+	q := resources.Qualifiers{Locale: locale, Config: o.config}
+	return filepath.Join(resDir, q.Dir(), stringsFilename)
+}
+
+// Returns the resources `base` with the strings, plurals and arrays overridden by the `overrides`.
+func overlaid(base, overrides *resources.Resources) *resources.Resources {
+	merged := *base
+	merged.Strings = nil
+	for _, el := range base.Strings {
+		if o := overrides.FindString(el.Name); o != nil {
+			el.Value, el.CompiledValue, el.RawValue = o.Value, o.CompiledValue, o.RawValue
+		}
+		merged.Strings = append(merged.Strings, el)
+	}
+	merged.Plurals = nil
+	for _, el := range base.Plurals {
+		if o := overrides.FindPlural(el.Name); o != nil {
+			el.Items = o.Items
+		}
+		merged.Plurals = append(merged.Plurals, el)
+	}
+	merged.StringArrays = nil
+	for _, el := range base.StringArrays {
+		if o := overrides.FindStringArray(el.Name); o != nil {
+			el.Items = o.Items
+		}
+		merged.StringArrays = append(merged.StringArrays, el)
+	}
+	return &merged
+}
+
+// Returns the names of the translatable strings, plurals and arrays overridden in the `overrides`.
+func overriddenNames(base, overrides *resources.Resources) []string {
+	var names []string
+	for _, el := range overrides.Strings {
+		if b := base.FindString(el.Name); b != nil && b.IsTranslatable() {
+			names = append(names, el.Name)
+		}
+	}
+	for _, el := range overrides.Plurals {
+		if b := base.FindPlural(el.Name); b != nil && b.IsTranslatable() {
+			names = append(names, el.Name)
+		}
+	}
+	for _, el := range overrides.StringArrays {
+		if b := base.FindStringArray(el.Name); b != nil && b.IsTranslatable() {
+			names = append(names, el.Name)
+		}
+	}
+	return names
+}
+
+// Returns true if the `res` have a string, plurals or an array named `name`.
+func hasResource(res *resources.Resources, name string) bool {
+	return res.FindString(name) != nil || res.FindPlural(name) != nil || res.FindStringArray(name) != nil
+}
+
+// Validates the overlay `o`: the base overlay is compared with the `baseResources`
+// (so the placeholders stay consistent with the strings without the qualifiers), and the translated overlays with the
+// base resources overridden by the base overlay. If `options.ShowMissing` is true, the strings overridden
+// in the base overlay are reported as missing for every one of the `translatedLocales` which does not translate them
+// in the overlay, since Android prefers the translation without the qualifiers to the untranslated overlay string
+// (the locale takes precedence over the other qualifiers except MCC and MNC). The files are parsed with `parse`.
+func validateOverlay(resDir, stringsFilename string, o *overlay, baseResources *resources.Resources, translatedLocales []string, options Options, parse parseFunc) []error {
+	var errorList []error
+	overlayOptions := options
+	overlayOptions.ShowMissing = false
+	overlayOptions.Suggest = false
+
+	overlayBase := &resources.Resources{}
+	if path, ok := o.paths[""]; ok {
+		res, err := parse(path)
+		if err != nil {
+			return []error{err}
+		}
+		shortPath := resources.ShortPath(resDir, path)
+		errorList = append(errorList, withoutIgnored(validateResources(baseResources, res, shortPath, overlayOptions), baseResources, res)...)
+		overlayBase = res
+	}
+	mergedBase := overlaid(baseResources, overlayBase)
+	overridden := overriddenNames(baseResources, overlayBase)
+
+	locales := make(map[string]bool)
+	for locale := range o.paths {
+		if len(locale) > 0 {
+			locales[locale] = true
+		}
+	}
+	for _, locale := range translatedLocales {
+		locales[locale] = true
+	}
+	var sortedLocales []string
+	for locale := range locales {
+		sortedLocales = append(sortedLocales, locale)
+	}
+	sort.Strings(sortedLocales)
+
+	for _, locale := range sortedLocales {
+		path, ok := o.paths[locale]
+		if !ok {
+			path = o.pathFor(resDir, locale, stringsFilename)
+		}
+		shortPath := resources.ShortPath(resDir, path)
+		if !options.Config.IsLocaleIncluded(filepath.Join(resources.ValuesDir(locale), stringsFilename)) {
+			continue
+		}
+		res := &resources.Resources{}
+		if ok {
+			parsed, err := parse(path)
+			if err != nil {
+				errorList = append(errorList, err)
+				continue
+			}
+			res = parsed
+			errorList = append(errorList, withoutIgnored(validateResources(mergedBase, res, shortPath, overlayOptions), baseResources, overlayBase, res)...)
+		}
+		if !options.ShowMissing || !options.Config.IsRuleEnabled(RuleMissing) {
+			continue
+		}
+		for _, name := range overridden {
+			if !hasResource(res, name) && !overlayBase.IsIgnored(name, RuleMissing) {
+				missingError := missingResourceError(name, shortPath)
+				missingError.msg += fmt.Sprintf(" (overridden for %s)", o.description)
+				errorList = append(errorList, missingError)
+			}
+		}
+	}
+	return errorList
+}
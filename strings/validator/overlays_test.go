@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Writes the strings files with the `values` (keyed by the values directory) into a new res directory,
+// each file declaring one string "greeting". Returns the res directory.
+func writeGreetings(t *testing.T, values map[string]string) string {
+	resDir := t.TempDir()
+	for dir, value := range values {
+		writeFile(t, filepath.Join(resDir, dir, "strings.xml"), "<resources>\n    <string name=\"greeting\">"+value+"</string>\n</resources>\n")
+	}
+	return resDir
+}
+
+// Writes the `content` to the file at `path`, creating its directory.
+func writeFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Returns the errors of the `rule` reported for the file at `path`.
+func errorsOf(errorList []error, path, rule string) []error {
+	var found []error
+	for _, err := range errorList {
+		if p, _, r := errorLocation(err); p == path && r == rule {
+			found = append(found, err)
+		}
+	}
+	return found
+}
+
+func TestOverlayPlaceholdersAreComparedWithTheOverlayBase(t *testing.T) {
+	tests := []struct {
+		name        string
+		translation string
+		wantErrors  int
+	}{
+		{"same placeholders as the overlay base", "Hallo %s", 0},
+		{"placeholder missing", "Hallo", 1},
+		{"quoted value with the placeholder", "\"Hallo  %s\"", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resDir := writeGreetings(t, map[string]string{
+				"values":          "Hello",
+				"values-night":    "Hello %s",
+				"values-de-night": test.translation,
+			})
+			errorList := Validate(resDir, "", "strings.xml", Options{})
+			if found := errorsOf(errorList, "values-de-night/strings.xml", RuleSimplePlaceholders); len(found) != test.wantErrors {
+				t.Errorf("got %d placeholder error(s), want %d: %v", len(found), test.wantErrors, found)
+			}
+		})
+	}
+}
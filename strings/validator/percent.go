@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"regexp"
+	"strings"
+)
+
+// Matches the Java format specifiers, e.g. %s, %1$d, %,d or %.2f.
+// The space flag is not matched, since "% d" in a translation is almost always a typo of a literal percent.
+var FormatSpecifierRegex *regexp.Regexp = regexp.MustCompile("%([0-9]+\\$)?[-#+0,(<]*[0-9]*(\\.[0-9]+)?[tT]?[a-zA-Z]")
+
+// Returns the `s` without the escaped literal percents ("%%"), so that they are not taken for placeholders.
+func withoutEscapedPercents(s string) string {
+	return strings.Replace(s, "%%", "", -1)
+}
+
+// Validates that a formatted string (i.e. having at least one format specifier) does not have
+// a bare "%" which is not a part of a valid format specifier; a literal percent must be written as "%%".
+func validateBarePercent(elemValue string) error {
+	s := withoutEscapedPercents(elemValue)
+	if !FormatSpecifierRegex.MatchString(s) {
+		return nil
+	}
+	if strings.Contains(FormatSpecifierRegex.ReplaceAllString(s, ""), "%") {
+		return errors.New("The formatted string has a bare '%', which is not a valid format specifier; a literal percent should be written as '%%'")
+	}
+	return nil
+}
+
+// Validates that the strings and plurals in `res` which have no format specifiers do not have a literal '%'
+// unless it is escaped as "%%" or the string is marked with formatted="false"; otherwise
+// formatting the string with arguments (e.g. `getString(id, args)`) crashes at runtime.
+// The strings with format specifiers are checked by the "bare-percent" rule.
+func validatePercentSafety(res *resources.Resources, shortPath string) []error {
+	var errorList []error
+	unsafe := func(value string) bool {
+		s := withoutEscapedPercents(value)
+		return strings.Contains(s, "%") && !FormatSpecifierRegex.MatchString(s)
+	}
+	for _, el := range res.Strings {
+		if el.IsFormatted() && unsafe(el.Value) {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The value '%s' has a literal '%%' which crashes formatting; escape it as '%%%%' or add formatted=\"false\"", el.Name, shortPath, el.Value), shortPath, el.Name, RulePercentSafety, nil, ""})
+		}
+	}
+	for _, el := range res.Plurals {
+		// getQuantityString() is almost always called with the quantity as an argument
+		for _, item := range el.Items {
+			if unsafe(item.Value) {
+				errorList = append(errorList, &ValidationError{fmt.Sprintf("%s (%s) in %s: The value '%s' has a literal '%%' which crashes formatting; escape it as '%%%%'", el.Name, item.Quantity, shortPath, item.Value), shortPath, el.Name, RulePercentSafety, nil, ""})
+				break
+			}
+		}
+	}
+	return errorList
+}
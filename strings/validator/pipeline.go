@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/glossary"
+	"github.com/armatys/android-tools/strings/resources"
+)
+
+// A value of a string, a plural item or a string-array item: the text content, the text content as compiled by aapt
+// (see `resources.CompileValue`) and the inner XML.
+type ruleValue struct {
+	value         string
+	compiledValue string
+	rawValue      string
+}
+
+// A value validated by the `valuePipeline`, with what the rules need to know about the element declaring it.
+type pipelineValue struct {
+	// The name of the resource.
+	name string
+	// Identifies the value in the messages, e.g. "days (one)" for a plural item.
+	label string
+	value ruleValue
+	// The base value, with which the comparison rules compare the value; they are skipped if it is nil.
+	base *ruleValue
+	// Another base value accepted by the comparison rules (e.g. the "other" plural item); may be nil.
+	alternativeBase *ruleValue
+	// False for the strings marked with formatted="false", for which the placeholder rules are skipped.
+	formatted bool
+	// True if the value is resolved from a referenced string (e.g. an array item "@string/name"),
+	// which is checked by the simple rules on its own, and has no mechanical fix.
+	referenced bool
+}
+
+// The rules enabled for a validated file. Every value-bearing element (strings, plural items and string-array items)
+// goes through the same pipeline, so that a rule added to `comparisonRules` or `simpleRules` applies to all of them.
+type valuePipeline struct {
+	shortPath       string
+	comparisonRules []comparisonRule
+	simpleRules     []simpleRule
+}
+
+// Returns the pipeline with the rules enabled in the `config` for the `validatedResources` at `shortPath`.
+func newValuePipeline(validatedResources *resources.Resources, shortPath string, config *Config, terms glossary.Glossary) *valuePipeline {
+	p := &valuePipeline{shortPath: shortPath}
+	locale := validatedResources.ResolveLocale(shortPath)
+	for _, rule := range comparisonRules {
+		if config.IsRuleEnabled(rule.id) {
+			p.comparisonRules = append(p.comparisonRules, rule)
+		}
+	}
+	if config.IsRuleEnabled(RuleIdenticalToBase) {
+		var allowed []string
+		if config != nil {
+			allowed = config.IdenticalAllowed
+		}
+		p.comparisonRules = append(p.comparisonRules, comparisonRule{RuleIdenticalToBase, identicalValidation(allowed), true})
+	}
+	if terms != nil && config.IsRuleEnabled(RuleGlossary) {
+		p.comparisonRules = append(p.comparisonRules, comparisonRule{RuleGlossary, glossaryValidation(terms, locale), false})
+	}
+	if config.IsRuleEnabled(RuleEndingPunctuation) {
+		punctuation := config.punctuationFor(locale)
+		p.comparisonRules = append(p.comparisonRules, comparisonRule{RuleEndingPunctuation, endingPunctuationValidation(punctuation), false})
+	}
+	if config.IsRuleEnabled(RuleQuoteStyle) {
+		convention := conventionQuoteStyle(config.typographyFor(locale))
+		p.comparisonRules = append(p.comparisonRules, comparisonRule{RuleQuoteStyle, quoteStyleValidation(convention, localeQuoteStyle(validatedResources)), false})
+	}
+	for _, rule := range simpleRules {
+		if config.IsRuleEnabled(rule.id) {
+			p.simpleRules = append(p.simpleRules, rule)
+		}
+	}
+	if typography := config.typographyFor(locale); typography != nil && config.IsRuleEnabled(RuleTypography) {
+		p.simpleRules = append(p.simpleRules, simpleRule{RuleTypography, typographyValidation(typography)})
+	}
+	if policy := config.nonBreakingSpaceFor(locale); len(policy) > 0 && config.IsRuleEnabled(RuleNonBreakingSpace) {
+		p.simpleRules = append(p.simpleRules, simpleRule{RuleNonBreakingSpace, nonBreakingSpaceValidation(policy)})
+	}
+	if config.IsRuleEnabled(RuleBidi) {
+		p.simpleRules = append(p.simpleRules, simpleRule{RuleBidi, bidiValidation(locale, config.IsStrictBidi())})
+	}
+	return p
+}
+
+// Validates the value with the comparison rules (if it has a base value) and the simple rules.
+func (p *valuePipeline) validate(v pipelineValue) []error {
+	var errorList []error
+	if v.base != nil {
+		for _, rule := range p.comparisonRules {
+			if !v.formatted && placeholderRules[rule.id] {
+				continue
+			}
+			err := rule.apply(*v.base, v.value)
+			if err != nil && v.alternativeBase != nil && rule.apply(*v.alternativeBase, v.value) == nil {
+				err = nil
+			}
+			if err != nil {
+				errorList = append(errorList, p.error(v, rule.id, v.base.rawValue, err))
+			}
+		}
+	}
+	if v.referenced {
+		return errorList
+	}
+	for _, rule := range p.simpleRules {
+		if !v.formatted && placeholderRules[rule.id] {
+			continue
+		}
+		if err := rule.fn(v.value.value); err != nil {
+			var baseRawValue string
+			if v.base != nil {
+				baseRawValue = v.base.rawValue
+			}
+			errorList = append(errorList, p.error(v, rule.id, baseRawValue, err))
+		}
+	}
+	return errorList
+}
+
+// Returns the error of the `rule` for the value, with the suggested fix.
+func (p *valuePipeline) error(v pipelineValue, rule, baseRawValue string, err error) error {
+	var fix string
+	if !v.referenced {
+		fix = suggestFix(rule, baseRawValue, v.value.rawValue)
+	}
+	return &ValidationError{fmt.Sprintf("%s in %s: %s", v.label, p.shortPath, err.Error()), p.shortPath, v.name, rule, nil, fix}
+}
+
+// Runs the rule on the `value` and the `base` value, or on their raw or compiled values if the rule is given those.
+func (rule comparisonRule) apply(base, value ruleValue) error {
+	if rule.raw {
+		return rule.fn(base.rawValue, value.rawValue)
+	}
+	if compiledValueRules[rule.id] {
+		return rule.fn(base.compiledValue, value.compiledValue)
+	}
+	return rule.fn(base.value, value.value)
+}
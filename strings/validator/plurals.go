@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"sort"
+	"strings"
+)
+
+// The plural categories of a language (from the CLDR cardinal plural rules): the `required` ones are selected
+// for the integer counts, and the `optional` ones only for the decimal or the very large (e.g. "1 million") counts.
+type pluralCategories struct {
+	required []string
+	optional []string
+}
+
+var otherOnly = pluralCategories{[]string{"other"}, nil}
+var oneOther = pluralCategories{[]string{"one", "other"}, nil}
+var oneOtherMany = pluralCategories{[]string{"one", "other"}, []string{"many"}}
+var oneFewManyOther = pluralCategories{[]string{"one", "few", "many", "other"}, nil}
+var oneFewOther = pluralCategories{[]string{"one", "few", "other"}, nil}
+var oneFewOtherMany = pluralCategories{[]string{"one", "few", "other"}, []string{"many"}}
+
+// The plural categories of the languages, keyed by the language code.
+// The locales of the languages not listed here are not checked by the "plural-quantities" rule.
+var pluralCategoriesByLanguage = map[string]pluralCategories{
+	"ja": otherOnly, "ko": otherOnly, "zh": otherOnly, "th": otherOnly, "vi": otherOnly, "id": otherOnly,
+	"in": otherOnly, "ms": otherOnly, "lo": otherOnly, "km": otherOnly, "my": otherOnly,
+
+	"en": oneOther, "de": oneOther, "nl": oneOther, "sv": oneOther, "da": oneOther, "nb": oneOther,
+	"nn": oneOther, "no": oneOther, "fi": oneOther, "et": oneOther, "el": oneOther, "hu": oneOther,
+	"tr": oneOther, "bg": oneOther, "ka": oneOther, "az": oneOther, "kk": oneOther, "uz": oneOther,
+	"sq": oneOther, "ur": oneOther, "hi": oneOther, "bn": oneOther, "ta": oneOther, "te": oneOther,
+	"mr": oneOther, "gu": oneOther, "kn": oneOther, "ml": oneOther, "sw": oneOther, "af": oneOther,
+	"eu": oneOther, "gl": oneOther, "is": oneOther, "fa": oneOther, "fil": oneOther, "am": oneOther,
+	"ne": oneOther, "si": oneOther, "pa": oneOther, "hy": oneOther, "mn": oneOther, "zu": oneOther,
+
+	"fr": oneOtherMany, "es": oneOtherMany, "it": oneOtherMany, "pt": oneOtherMany, "ca": oneOtherMany,
+
+	"pl": oneFewManyOther, "ru": oneFewManyOther, "uk": oneFewManyOther, "be": oneFewManyOther,
+	"hr": oneFewOther, "sr": oneFewOther, "bs": oneFewOther, "ro": oneFewOther, "mo": oneFewOther,
+	"cs": oneFewOtherMany, "sk": oneFewOtherMany, "lt": oneFewOtherMany,
+
+	"he": {[]string{"one", "two", "other"}, []string{"many"}},
+	"iw": {[]string{"one", "two", "other"}, []string{"many"}},
+	"sl": {[]string{"one", "two", "few", "other"}, nil},
+	"lv": {[]string{"zero", "one", "other"}, nil},
+	"ar": {[]string{"zero", "one", "two", "few", "many", "other"}, nil},
+	"cy": {[]string{"zero", "one", "two", "few", "many", "other"}, nil},
+	"ga": {[]string{"one", "two", "few", "many", "other"}, nil},
+}
+
+// Validates that the plurals in `res` declare the quantities used by the plural rules of the `locale`,
+// rather than the quantities of the base plurals: e.g. Japanese uses only "other" while English uses "one" and "other",
+// and Polish also needs "few" and "many". The quantities never selected in the locale (e.g. "zero" in English) are reported too.
+func validatePluralQuantities(res, baseResources *resources.Resources, locale, shortPath string) []error {
+	categories, ok := pluralCategoriesByLanguage[languageOf(locale)]
+	if !ok {
+		return nil
+	}
+	used := make(map[string]bool)
+	for _, c := range categories.required {
+		used[c] = true
+	}
+	for _, c := range categories.optional {
+		used[c] = true
+	}
+	var errorList []error
+	for _, el := range res.Plurals {
+		if base := baseResources.FindPlural(el.Name); base == nil || !base.IsTranslatable() || len(el.Items) == 0 {
+			continue
+		}
+		var missing, unused []string
+		for _, c := range categories.required {
+			// The missing "other" is reported by the "plural-other" rule.
+			if c != "other" && el.FindItem(c) == nil {
+				missing = append(missing, c)
+			}
+		}
+		for _, item := range el.Items {
+			if !used[item.Quantity] {
+				unused = append(unused, item.Quantity)
+			}
+		}
+		sort.Strings(unused)
+		if len(missing) > 0 {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s plurals in %s does not have the quantities used in this language: %s", el.Name, shortPath, strings.Join(missing, ", ")), shortPath, el.Name, RulePluralQuantities, nil, ""})
+		}
+		if len(unused) > 0 {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s plurals in %s has the quantities never used in this language: %s", el.Name, shortPath, strings.Join(unused, ", ")), shortPath, el.Name, RulePluralQuantities, nil, ""})
+		}
+	}
+	return errorList
+}
+
+// Validates that every plurals in `res` declares the "other" quantity, since getQuantityString() throws
+// an exception for a quantity whose category is not declared and there is no "other" to fall back to.
+func validatePluralOther(res *resources.Resources, shortPath string) []error {
+	var errorList []error
+	for _, el := range res.Plurals {
+		if el.FindItem("other") == nil {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s plurals in %s does not have the quantity=\"other\" item, which crashes the app for the quantities without an item", el.Name, shortPath), shortPath, el.Name, RulePluralOther, nil, ""})
+		}
+	}
+	return errorList
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// The position of a resource in a strings file, for the editors and the CI annotations.
+type Position struct {
+	// The short path of the file declaring the resource (e.g. "values-de/strings.xml");
+	// for a file set (see `resources.IsFileSet`) the file of the set.
+	File string
+	// The line and the column (starting with 1) of the resource's start tag.
+	Line   int
+	Column int
+}
+
+// Returns the position of the error, or nil if unknown (e.g. for a missing resource, which is not declared in the file).
+func PositionOf(err error) *Position {
+	switch e := err.(type) {
+	case *ValidationError:
+		return e.Position
+	case *DanglingReferenceError:
+		return e.Position
+	}
+	return nil
+}
+
+// Sets the positions of the errors of the resources declared in the strings files of the `resDir`,
+// reading each file with errors once.
+func setPositions(errorList []error, resDir string) {
+	files := make(map[string]*resources.Resources)
+	for _, err := range errorList {
+		path, key, _ := errorLocation(err)
+		if len(path) == 0 || len(key) == 0 {
+			continue
+		}
+		switch e := err.(type) {
+		case *ValidationError:
+			e.Position = findPosition(files, resDir, path, key)
+		case *DanglingReferenceError:
+			e.Position = findPosition(files, resDir, path, key)
+		}
+	}
+}
+
+// Returns the position of the resource `key` in the strings file (or the file set) at the short `path`,
+// or nil if it is not declared there. The parsed files are cached in `files`.
+func findPosition(files map[string]*resources.Resources, resDir, path, key string) *Position {
+	paths, err := resources.ExpandPath(filepath.Join(resDir, path))
+	if err != nil {
+		return nil
+	}
+	for _, p := range paths {
+		res, ok := files[p]
+		if !ok {
+			res, _ = resources.ParseFile(p)
+			files[p] = res
+		}
+		if res == nil {
+			continue
+		}
+		file := resources.ShortPath(resDir, p)
+		if el := res.FindString(key); el != nil {
+			return &Position{file, el.Line, el.Column}
+		}
+		if el := res.FindPlural(key); el != nil {
+			return &Position{file, el.Line, el.Column}
+		}
+		if el := res.FindStringArray(key); el != nil {
+			return &Position{file, el.Line, el.Column}
+		}
+	}
+	return nil
+}
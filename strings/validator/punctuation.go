@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// The ending punctuation conventions of a locale, for the "ending-punctuation" rule.
+type PunctuationConfig struct {
+	// The endings accepted in the translation instead of the base ending, e.g. {"?": [";"]} for Greek.
+	// The base ending itself is always accepted.
+	Equivalents map[string][]string
+	// If true, the ending punctuation of the locale is not checked (e.g. for Thai, which has no sentence punctuation).
+	Ignore bool
+}
+
+// The endings compared by the "ending-punctuation" rule.
+var endingPunctuation = []string{".", ":", "?", "!"}
+
+var fullWidthPunctuation = &PunctuationConfig{Equivalents: map[string][]string{".": {"。"}, ":": {"："}, "?": {"？"}, "!": {"！"}}}
+var arabicPunctuation = &PunctuationConfig{Equivalents: map[string][]string{"?": {"؟"}}}
+
+// The conventions of the languages whose punctuation differs from the Latin one;
+// they are used for the locales not configured in the `Punctuation` of the configuration.
+var defaultPunctuation = map[string]*PunctuationConfig{
+	"zh": fullWidthPunctuation,
+	"ja": fullWidthPunctuation,
+	"el": {Equivalents: map[string][]string{"?": {";"}}},
+	"ar": arabicPunctuation,
+	"fa": arabicPunctuation,
+	"ur": arabicPunctuation,
+	"hi": {Equivalents: map[string][]string{".": {"।"}}},
+	"hy": {Equivalents: map[string][]string{".": {"։"}}},
+	"th": {Ignore: true},
+}
+
+// Returns the punctuation conventions of the `locale`: the configured ones (by the locale, the language or "*"),
+// or the default ones of the language. Returns nil if the locale uses the Latin conventions.
+func (c *Config) punctuationFor(locale string) *PunctuationConfig {
+	if c != nil {
+		for _, key := range []string{locale, languageOf(locale), "*"} {
+			if p, ok := c.Punctuation[key]; ok {
+				return p
+			}
+		}
+	}
+	return defaultPunctuation[languageOf(locale)]
+}
+
+// Returns the punctuation ending the `s` (one of `endingPunctuation`, or an equivalent from the `punctuation`
+// mapped back to it), or an empty string if `s` does not end with punctuation. The ellipsis ends like a period.
+func endingOf(s string, punctuation *PunctuationConfig) string {
+	s = strings.TrimRight(unquoted(s), " \t\n")
+	if strings.HasSuffix(s, "…") {
+		return "."
+	}
+	for _, ending := range endingPunctuation {
+		if strings.HasSuffix(s, ending) {
+			return ending
+		}
+	}
+	if punctuation != nil {
+		for ending, equivalents := range punctuation.Equivalents {
+			for _, equivalent := range equivalents {
+				if len(equivalent) > 0 && strings.HasSuffix(s, equivalent) {
+					return ending
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Returns a validation function checking that the translation ends with the same punctuation as the base string,
+// according to the `punctuation` conventions of the locale (may be nil).
+func endingPunctuationValidation(punctuation *PunctuationConfig) comparisonValidation {
+	return func(baseElemString, validatedElemString string) error {
+		if punctuation != nil && punctuation.Ignore {
+			return nil
+		}
+		if utf8.RuneCountInString(strings.TrimSpace(unquoted(validatedElemString))) == 0 {
+			// Reported by the "empty-translation" rule.
+			return nil
+		}
+		baseEnding, targetEnding := endingOf(baseElemString, nil), endingOf(validatedElemString, punctuation)
+		if baseEnding == targetEnding {
+			return nil
+		}
+		if len(targetEnding) == 0 {
+			return errors.New(fmt.Sprintf("The base string ends with '%s', but the target string does not", baseEnding))
+		}
+		if len(baseEnding) == 0 {
+			return errors.New(fmt.Sprintf("The target string ends with '%s', but the base string does not", targetEnding))
+		}
+		return errors.New(fmt.Sprintf("The base string ends with '%s', but the target string ends with '%s'", baseEnding, targetEnding))
+	}
+}
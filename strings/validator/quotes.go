@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+)
+
+// The quote styles compared by the "quote-style" rule.
+const (
+	quoteStyleNone        = ""
+	quoteStyleStraight    = "straight"
+	quoteStyleTypographic = "typographic"
+	quoteStyleMixed       = "mixed"
+)
+
+// The straight double quote, which must be escaped in Android strings to be shown.
+const straightQuote = "\\\""
+
+// Returns the style of the double quotes used in the value: straight (\"), typographic (e.g. “ or «),
+// mixed if both are used, or none.
+func quoteStyleOf(value string) string {
+	straight := strings.Contains(value, straightQuote)
+	typographic := false
+	for _, quote := range typographyQuotes {
+		if quote != straightQuote && strings.Contains(value, quote) {
+			typographic = true
+			break
+		}
+	}
+	switch {
+	case straight && typographic:
+		return quoteStyleMixed
+	case straight:
+		return quoteStyleStraight
+	case typographic:
+		return quoteStyleTypographic
+	}
+	return quoteStyleNone
+}
+
+// Returns the quote style of the quotation marks configured in the `typography` conventions, or none if there are none.
+func conventionQuoteStyle(typography *TypographyConfig) string {
+	if typography == nil || len(typography.Quotes) == 0 {
+		return quoteStyleNone
+	}
+	for _, quote := range typography.Quotes {
+		if quote != straightQuote && quote != "\"" {
+			return quoteStyleTypographic
+		}
+	}
+	return quoteStyleStraight
+}
+
+// Returns the quote style used by most of the values of the `res` with quotes, or none if neither is used more.
+func localeQuoteStyle(res *resources.Resources) string {
+	counts := make(map[string]int)
+	for _, el := range res.Strings {
+		counts[quoteStyleOf(el.Value)] += 1
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			counts[quoteStyleOf(item.Value)] += 1
+		}
+	}
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			counts[quoteStyleOf(item.Value)] += 1
+		}
+	}
+	switch {
+	case counts[quoteStyleStraight] > counts[quoteStyleTypographic]:
+		return quoteStyleStraight
+	case counts[quoteStyleTypographic] > counts[quoteStyleStraight]:
+		return quoteStyleTypographic
+	}
+	return quoteStyleNone
+}
+
+// Returns a validation function checking that the translation does not mix the straight and the typographic quotes,
+// and that it uses the quote style of the `convention` of the locale, or if there is none, the style of the base string,
+// or if it has no quotes, the style used by most strings of the locale (`localeStyle`).
+func quoteStyleValidation(convention, localeStyle string) comparisonValidation {
+	return func(baseElemString, validatedElemString string) error {
+		style := quoteStyleOf(validatedElemString)
+		if style == quoteStyleNone {
+			return nil
+		}
+		if style == quoteStyleMixed {
+			return errors.New("The target string mixes the straight (\\\") and the typographic quotes")
+		}
+		expected, source := convention, "the locale convention"
+		if expected == quoteStyleNone {
+			expected, source = quoteStyleOf(baseElemString), "the base string"
+		}
+		if expected == quoteStyleNone || expected == quoteStyleMixed {
+			expected, source = localeStyle, "most strings of the locale"
+		}
+		if expected == quoteStyleNone || expected == style {
+			return nil
+		}
+		return errors.New(fmt.Sprintf("The target string uses the %s quotes instead of the %s ones of %s", style, expected, source))
+	}
+}
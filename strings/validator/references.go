@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+)
+
+// Validates the @string references in `res`: the referenced strings must be declared in the `baseResources`
+// (see `DanglingReferenceError`), and the references must not form a cycle.
+// `res` may be the `baseResources`.
+func validateStringReferences(res, baseResources *resources.Resources, shortPath string) []error {
+	find := func(name string) *resources.String {
+		if el := res.FindString(name); el != nil {
+			return el
+		}
+		return baseResources.FindString(name)
+	}
+
+	var errorList []error
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			if target := item.Reference(); len(target) > 0 && baseResources.FindString(target) == nil {
+				errorList = append(errorList, &DanglingReferenceError{fmt.Sprintf("%s array in %s references @string/%s, which is not declared in the base resources", el.Name, shortPath, target), shortPath, el.Name, target, nil})
+			}
+		}
+	}
+	for _, el := range res.Strings {
+		target := el.Reference()
+		if len(target) == 0 {
+			continue
+		}
+		if baseResources.FindString(target) == nil {
+			errorList = append(errorList, &DanglingReferenceError{fmt.Sprintf("%s in %s references @string/%s, which is not declared in the base resources", el.Name, shortPath, target), shortPath, el.Name, target, nil})
+			continue
+		}
+		chain := []string{el.Name}
+		visited := map[string]bool{el.Name: true}
+		for len(target) > 0 {
+			next := find(target)
+			if next == nil {
+				// The dangling reference is reported for the string that has it.
+				break
+			}
+			if visited[target] {
+				// The cycle is reported for the strings in it, not for the ones referencing it.
+				if target == el.Name {
+					chain = append(chain, target)
+					errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s has a circular reference: %s", el.Name, shortPath, strings.Join(chain, " -> ")), shortPath, el.Name, RuleStringReference, nil, ""})
+				}
+				break
+			}
+			chain = append(chain, target)
+			visited[target] = true
+			target = next.Reference()
+		}
+	}
+	return errorList
+}
+
+// Returns the `item` of a string-array in `res` with the value (and the raw value) of the string it references
+// (following the chain of references), looked up in `res` and then in the `baseResources`.
+// Returns the name of the resolved string (empty if the item is not a reference), and false
+// if the reference is dangling or circular (see `validateStringReferences`).
+func resolveArrayItem(item resources.StringArrayItem, res, baseResources *resources.Resources) (resources.StringArrayItem, string, bool) {
+	target := item.Reference()
+	name := ""
+	visited := make(map[string]bool)
+	for len(target) > 0 {
+		if visited[target] {
+			return item, "", false
+		}
+		visited[target] = true
+		el := res.FindString(target)
+		if el == nil {
+			el = baseResources.FindString(target)
+		}
+		if el == nil {
+			return item, "", false
+		}
+		name = target
+		item = resources.StringArrayItem{Value: el.Value, CompiledValue: el.CompiledValue, RawValue: el.RawValue}
+		target = el.Reference()
+	}
+	return item, name, true
+}
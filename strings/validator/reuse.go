@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+)
+
+// The default minimal similarity (0-1) of two base values for the string reuse suggestion.
+const defaultReuseThreshold = 0.9
+
+// Suggests reusing an existing base string, when a string declared later in the file
+// has a value similar to it (see `similarity`). The non-translatable strings are skipped.
+func validateStringReuse(baseResources *resources.Resources, shortPath string, threshold float64) []error {
+	if threshold <= 0 {
+		threshold = defaultReuseThreshold
+	}
+	var errorList []error
+	var previous []resources.String
+	for _, el := range baseResources.Strings {
+		if !el.IsTranslatable() || len(strings.TrimSpace(el.Value)) == 0 {
+			continue
+		}
+		bestScore := 0.0
+		var best *resources.String
+		for i := range previous {
+			if score := similarity(el.Value, previous[i].Value); score >= threshold && score > bestScore {
+				bestScore = score
+				best = &previous[i]
+			}
+		}
+		if best != nil {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The value is %.0f%% similar to %s ('%s'), consider reusing the existing string", el.Name, shortPath, bestScore*100, best.Name, best.Value), shortPath, el.Name, RuleStringReuse, nil, ""})
+		}
+		previous = append(previous, el)
+	}
+	return errorList
+}
+
+// Returns the similarity of the strings between 0 (different) and 1 (equal, ignoring case and surrounding spaces),
+// based on the Levenshtein distance.
+func similarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(strings.TrimSpace(a)))
+	rb := []rune(strings.ToLower(strings.TrimSpace(b)))
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+func levenshtein(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next := row[j] + 1
+			if row[j-1]+1 < next {
+				next = row[j-1] + 1
+			}
+			if diagonal+cost < next {
+				next = diagonal + cost
+			}
+			diagonal = row[j]
+			row[j] = next
+		}
+	}
+	return row[len(b)]
+}
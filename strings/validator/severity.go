@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The severities of the rules.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// The severities of the built-in rules that are not errors.
+var defaultSeverities = map[string]string{
+	RuleStringReuse:         SeverityInfo,
+	RuleCasing:              SeverityWarning,
+	RuleIdenticalToBase:     SeverityWarning,
+	RulePercentSafety:       SeverityWarning,
+	RuleEscapeParity:        SeverityWarning,
+	RuleEndingPunctuation:   SeverityWarning,
+	RuleNumbers:             SeverityWarning,
+	RuleBidi:                SeverityWarning,
+	RuleSpelling:            SeverityWarning,
+	RulePluralQuantities:    SeverityWarning,
+	RuleSortedNames:         SeverityWarning,
+	RuleStaleTranslation:    SeverityWarning,
+	RuleQuoteStyle:          SeverityWarning,
+	RulePlaceholderIndexing: SeverityWarning,
+}
+
+// The ranks of the severities; a higher rank is more severe.
+var severityRanks = map[string]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// Returns true if `severity` is a known severity.
+func IsSeverity(severity string) bool {
+	_, ok := severityRanks[severity]
+	return ok
+}
+
+// Returns true if the `severity` is at least as severe as the `threshold`.
+func IsAtLeast(severity, threshold string) bool {
+	return severityRanks[severity] >= severityRanks[threshold]
+}
+
+// Returns the severity of the rule with the `id`: the one from the `Severities` of the configuration,
+// the one declared by the custom rule, or the default one (the built-in rules are mostly errors).
+func (c *Config) Severity(id string) string {
+	if c != nil {
+		if severity, ok := c.Severities[id]; ok {
+			return severity
+		}
+		for _, rule := range c.CustomRules {
+			if rule.ID == id && len(rule.Severity) > 0 {
+				return rule.Severity
+			}
+		}
+	}
+	if severity, ok := defaultSeverities[id]; ok {
+		return severity
+	}
+	return SeverityError
+}
+
+// Returns the severity of the error returned by the validation.
+// The errors not reported by the rules (e.g. I/O errors) are always errors.
+func (c *Config) SeverityOf(err error) string {
+	if _, _, rule := errorLocation(err); len(rule) > 0 {
+		return c.Severity(rule)
+	}
+	return SeverityError
+}
+
+func (c *Config) checkSeverities() error {
+	for id, severity := range c.Severities {
+		if !IsSeverity(severity) {
+			return errors.New(fmt.Sprintf("The rule '%s' has an unknown severity '%s'.", id, severity))
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		if err := profile.checkSeverities(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
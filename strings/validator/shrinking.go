@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/usage"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The resource shrinker configuration, usually declared in "res/raw/keep.xml".
+type keepRulesEl struct {
+	Keep       string `xml:"keep,attr"`
+	Discard    string `xml:"discard,attr"`
+	ShrinkMode string `xml:"shrinkMode,attr"`
+}
+
+// Reads the shrinker keep rules at `path`.
+// Returns the string name patterns to keep, the patterns to discard and whether the strict mode is enabled.
+func parseKeepRules(keepRulesPath string) (keep, discard []string, strict bool, err error) {
+	data, err := ioutil.ReadFile(keepRulesPath)
+	if err != nil {
+		return
+	}
+	var rules keepRulesEl
+	if err = xml.Unmarshal(data, &rules); err != nil {
+		return
+	}
+	return stringPatterns(rules.Keep), stringPatterns(rules.Discard), rules.ShrinkMode == "strict", nil
+}
+
+// Extracts the string name patterns from a comma-separated list like "@string/foo,@layout/bar,@string/baz_*".
+func stringPatterns(resourceList string) []string {
+	var patterns []string
+	for _, res := range strings.Split(resourceList, ",") {
+		res = strings.TrimSpace(res)
+		if strings.HasPrefix(res, "@string/") {
+			patterns = append(patterns, strings.TrimPrefix(res, "@string/"))
+		}
+	}
+	return patterns
+}
+
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, patt := range patterns {
+		if matched, _ := path.Match(patt, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]usage.Reference) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reports translated strings that are needed at runtime, but are likely to be stripped by the resource shrinker.
+// A string is considered needed if its name matches one of the `dynamicKeys` patterns,
+// or if it is looked up with `getIdentifier` in the sources at `srcDir` (in the strict shrink mode only,
+// since the safe mode keeps such strings anyway).
+// The strings are kept if they match the keep rules in the `keepRulesPath` file (unless they are also discarded there).
+func ValidateShrinkSafety(resDir, baseLocale, stringsFilename, keepRulesPath string, dynamicKeys []string, srcDir string) (errorList []error) {
+	errorList = make([]error, 0)
+	baseResources, err := resources.Parse(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		errorList = append(errorList, err)
+		return
+	}
+
+	var keep, discard []string
+	strict := false
+	if len(keepRulesPath) > 0 {
+		keep, discard, strict, err = parseKeepRules(keepRulesPath)
+		if err != nil {
+			errorList = append(errorList, err)
+			return
+		}
+	}
+
+	var lookups map[string][]usage.Reference
+	if strict && len(srcDir) > 0 {
+		lookups, err = usage.FindIdentifierLookups(srcDir)
+		if err != nil {
+			errorList = append(errorList, err)
+			return
+		}
+	}
+
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		errorList = append(errorList, err)
+		return
+	}
+	translationCounts := make(map[string]int)
+	for _, p := range paths {
+		localeResources, err := resources.ParseFile(p)
+		if err != nil {
+			errorList = append(errorList, err)
+			continue
+		}
+		for _, el := range localeResources.Strings {
+			translationCounts[el.Name] += 1
+		}
+	}
+
+	basePath := filepath.Join(resources.ValuesDir(baseLocale), stringsFilename)
+	for _, baseElem := range baseResources.Strings {
+		var reason string
+		if matchesAnyPattern(baseElem.Name, dynamicKeys) {
+			reason = "listed as a dynamic key"
+		}
+		for _, patt := range sortedKeys(lookups) {
+			if matched, _ := path.Match(patt, baseElem.Name); matched {
+				refs := lookups[patt]
+				reason = fmt.Sprintf("looked up with getIdentifier at %s:%d", refs[0].Path, refs[0].Line)
+				break
+			}
+		}
+		if len(reason) == 0 || translationCounts[baseElem.Name] == 0 {
+			continue
+		}
+		if !matchesAnyPattern(baseElem.Name, keep) || matchesAnyPattern(baseElem.Name, discard) {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s is %s and translated in %d locale(s), but it is likely to be stripped by the resource shrinker (not kept by the keep rules)", baseElem.Name, reason, translationCounts[baseElem.Name]), basePath, baseElem.Name, RuleShrinkSafety, nil, ""})
+		}
+	}
+
+	sortErrors(errorList)
+	return
+}
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Validates that the resources in the strings file at `path` are declared in the alphabetical order of their names
+// (ignoring the case). Only the first out-of-order declaration of each file is reported, as the rest of the file
+// is usually out of order because of it. The files of a file set (see `resources.IsFileSet`) are validated one by one.
+func validateSortedNames(path, shortPath string) []error {
+	if resources.IsFileSet(filepath.Base(path)) {
+		paths, err := resources.ExpandPath(path)
+		if err != nil {
+			return []error{err}
+		}
+		var errorList []error
+		for _, p := range paths {
+			errorList = append(errorList, validateSortedNames(p, filepath.Join(filepath.Dir(shortPath), filepath.Base(p)))...)
+		}
+		return errorList
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	declarations, err := resources.ParseDeclarations(data)
+	if err != nil {
+		return []error{err}
+	}
+	for i := 1; i < len(declarations); i++ {
+		previous, d := declarations[i-1], declarations[i]
+		if resources.LessName(d.Name, previous.Name) {
+			return []error{&ValidationError{fmt.Sprintf("%s in %s: The <%s> at line %d is not sorted by name; it should be declared before %s", d.Name, shortPath, d.Element, d.Line, previous.Name), shortPath, d.Name, RuleSortedNames, nil, ""}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// A type of function that parses the resources of the strings file at `path`.
+type parseFunc func(path string) (*resources.Resources, error)
+
+// Returns the function parsing the strings files of the `resDir` merged over the same files of the `sourceSets`
+// (the res directories of the source sets with a lower priority, e.g. "src/main/res"), the way the Android Gradle
+// plugin merges them. Returns `resources.ParseFile` if there are no `sourceSets`.
+func sourceSetParser(resDir string, sourceSets []string) parseFunc {
+	if len(sourceSets) == 0 {
+		return resources.ParseFile
+	}
+	return func(path string) (*resources.Resources, error) {
+		rel, err := filepath.Rel(resDir, path)
+		if err != nil {
+			return nil, err
+		}
+		var all []*resources.Resources
+		for _, dir := range append(sourceSets, resDir) {
+			if p := filepath.Join(dir, rel); isFile(p) {
+				res, err := resources.ParseFile(p)
+				if err != nil {
+					return nil, err
+				}
+				all = append(all, res)
+			}
+		}
+		if len(all) == 0 {
+			return resources.ParseFile(path)
+		}
+		return resources.Override(all), nil
+	}
+}
+
+// Returns the paths of the strings files of the other locales (see `resources.OtherLocalePaths`) in the `resDir`
+// or in any of the `sourceSets`, as the paths in the `resDir`.
+func sourceSetLocalePaths(resDir, baseLocale, stringsFilename string, sourceSets []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range append(sourceSets, resDir) {
+		dirPaths, err := resources.OtherLocalePaths(dir, baseLocale, stringsFilename)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range dirPaths {
+			path := filepath.Join(resDir, resources.ShortPath(dir, p))
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// Returns the `errorList` without the errors of the resources that are not declared in the `resDir` itself,
+// which are reported when validating the source sets that declare them.
+func declaredInSourceSet(errorList []error, resDir, baseLocale, stringsFilename string) []error {
+	paths, err := resources.OtherLocalePaths(resDir, baseLocale, stringsFilename)
+	if err != nil {
+		return append(errorList, err)
+	}
+	if basePath := filepath.Join(resDir, resources.ValuesDir(baseLocale), stringsFilename); isFile(basePath) {
+		paths = append(paths, basePath)
+	}
+	declared := make(map[string]bool)
+	for _, path := range paths {
+		if res, err := resources.ParseFile(path); err == nil {
+			for name := range res.Names() {
+				declared[name] = true
+			}
+		}
+	}
+	var filtered []error
+	for _, e := range errorList {
+		if _, key, _ := errorLocation(e); len(key) == 0 || declared[key] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/audit"
+	"github.com/armatys/android-tools/strings/resources"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// Matches the format specifiers that are valid on iOS, but not in Java, e.g. %@, %1$@, %ld, %lu or %i.
+var IOSSpecifierRegex *regexp.Regexp = regexp.MustCompile("%([0-9]+\\$)?(@|l{1,2}[diuf]|[iu])")
+
+// Matches the string and item elements in a resources file, with their content.
+var valueElementRegex *regexp.Regexp = regexp.MustCompile("(?s)(<(string|item)\\b[^>]*>)(.*?)(</(string|item)>)")
+
+// The Java conversions replacing the iOS ones.
+var iosConversions = map[string]string{
+	"@": "s", "i": "d", "u": "d",
+	"ld": "d", "li": "d", "lu": "d", "lf": "f",
+	"lld": "d", "lli": "d", "llu": "d", "llf": "f",
+}
+
+// Returns the `s` with the iOS format specifiers replaced with the Java ones (e.g. %@ with %s).
+func replaceIOSSpecifiers(s string) string {
+	return IOSSpecifierRegex.ReplaceAllStringFunc(s, func(specifier string) string {
+		match := IOSSpecifierRegex.FindStringSubmatch(specifier)
+		return "%" + match[1] + iosConversions[match[2]]
+	})
+}
+
+// Validates that `elemValue` does not contain the iOS format specifiers.
+func validateIOSSpecifiers(elemValue string) error {
+	specifiers := IOSSpecifierRegex.FindAllString(withoutEscapedPercents(elemValue), -1)
+	if len(specifiers) == 0 {
+		return nil
+	}
+	var suggestions []string
+	for _, specifier := range specifiers {
+		suggestions = append(suggestions, fmt.Sprintf("%s should be %s", specifier, replaceIOSSpecifiers(specifier)))
+	}
+	return errors.New(fmt.Sprintf("The string has format specifiers not supported on Android: %s", strings.Join(suggestions, ", ")))
+}
+
+// Matches the name attribute of an element.
+var nameAttrRegex *regexp.Regexp = regexp.MustCompile("\\bname=\"([^\"]*)\"")
+
+// Replaces the iOS format specifiers in the values of the strings file at `path` with the Java ones.
+// The strings with formatted="false" and the strings listed in `unformatted` (e.g. the ones with formatted="false"
+// in the base file) are skipped. Returns the number of replaced specifiers.
+func FixIOSSpecifiers(path string, unformatted map[string]bool) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	fixed := valueElementRegex.ReplaceAllStringFunc(string(data), func(element string) string {
+		match := valueElementRegex.FindStringSubmatch(element)
+		if strings.Contains(match[1], "formatted=\"false\"") {
+			return element
+		}
+		if name := nameAttrRegex.FindStringSubmatch(match[1]); name != nil && unformatted[name[1]] {
+			return element
+		}
+		count += len(IOSSpecifierRegex.FindAllString(match[3], -1))
+		return match[1] + replaceIOSSpecifiers(match[3]) + match[4]
+	})
+	if count == 0 {
+		return 0, nil
+	}
+	if _, err := resources.ParseData([]byte(fixed)); err != nil {
+		return 0, errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+	}
+	return count, audit.WriteFile(path, []byte(fixed), "fix:"+RuleIOSSpecifiers)
+}
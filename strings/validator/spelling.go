@@ -0,0 +1,123 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/armatys/android-tools/strings/brands"
+	"github.com/armatys/android-tools/strings/resources"
+	"github.com/armatys/android-tools/strings/spelling"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// The spellchecking of the translations with hunspell.
+type SpellingConfig struct {
+	// The hunspell dictionaries (names like "de_DE" or paths without the extension), keyed by the locale
+	// or the language (e.g. "pt-rBR" or "pt"). The locales without a dictionary are not checked.
+	Dictionaries map[string]string
+	// The project words which are not misspelled (e.g. the product names).
+	Whitelist []string
+	// The path to a file with more such words, one per line.
+	WhitelistFile string
+}
+
+// Matches the escape sequences of the string values, e.g. "\n" or " ".
+var escapeSequenceRegex *regexp.Regexp = regexp.MustCompile("\\\\(u[0-9a-fA-F]{4}|.)")
+
+// Returns the dictionary for the `locale`, or an empty string if there is none.
+func (s *SpellingConfig) dictionaryFor(locale string) string {
+	if dictionary, ok := s.Dictionaries[locale]; ok {
+		return dictionary
+	}
+	return s.Dictionaries[languageOf(locale)]
+}
+
+// Returns the whitelisted words, in lower case.
+func (s *SpellingConfig) whitelist() (map[string]bool, error) {
+	words := make(map[string]bool)
+	for _, word := range s.Whitelist {
+		words[strings.ToLower(word)] = true
+	}
+	if len(s.WhitelistFile) == 0 {
+		return words, nil
+	}
+	file, err := os.Open(s.WhitelistFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); len(word) > 0 {
+			words[strings.ToLower(word)] = true
+		}
+	}
+	return words, scanner.Err()
+}
+
+// Returns the text of the value to spellcheck, without the placeholders, the brand variables, the URLs,
+// the email addresses and the escape sequences.
+func spellableText(value string) string {
+	value = FormatSpecifierRegex.ReplaceAllString(withoutEscapedPercents(value), " ")
+	value = brands.VariableRegex.ReplaceAllString(value, " ")
+	value = URLRegex.ReplaceAllString(value, " ")
+	value = EmailRegex.ReplaceAllString(value, " ")
+	return escapeSequenceRegex.ReplaceAllStringFunc(value, func(escape string) string {
+		if escape == "\\'" || escape == "\\\"" {
+			return escape[1:]
+		}
+		return " "
+	})
+}
+
+// Spellchecks the values of the translation `res` of the `locale` (the markup is not a part of the values),
+// with the dictionary configured for the locale. Returns nil if there is no dictionary for the locale.
+func validateSpelling(res *resources.Resources, shortPath, locale string, config *SpellingConfig) []error {
+	dictionary := config.dictionaryFor(locale)
+	if len(dictionary) == 0 {
+		return nil
+	}
+	whitelist, err := config.whitelist()
+	if err != nil {
+		return []error{err}
+	}
+
+	var names, lines []string
+	for _, el := range res.Strings {
+		names, lines = append(names, el.Name), append(lines, spellableText(el.Value))
+	}
+	for _, el := range res.Plurals {
+		for _, item := range el.Items {
+			names, lines = append(names, el.Name), append(lines, spellableText(item.Value))
+		}
+	}
+	for _, el := range res.StringArrays {
+		for _, item := range el.Items {
+			names, lines = append(names, el.Name), append(lines, spellableText(item.Value))
+		}
+	}
+	results, err := spelling.Check(dictionary, lines)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errorList []error
+	for i, misspellings := range results {
+		var words []string
+		for _, m := range misspellings {
+			if whitelist[strings.ToLower(m.Word)] {
+				continue
+			}
+			word := fmt.Sprintf("'%s'", m.Word)
+			if len(m.Suggestions) > 0 {
+				word += fmt.Sprintf(" (did you mean '%s'?)", m.Suggestions[0])
+			}
+			words = append(words, word)
+		}
+		if len(words) > 0 {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: Possibly misspelled: %s", names[i], shortPath, strings.Join(words, ", ")), shortPath, names[i], RuleSpelling, nil, ""})
+		}
+	}
+	return errorList
+}
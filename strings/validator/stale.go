@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/git"
+	"github.com/armatys/android-tools/strings/resources"
+	"path/filepath"
+)
+
+// The strings files in a past git revision, compared with the current ones to find the stale translations:
+// the ones which were not updated when their base text changed.
+type Revision struct {
+	// The git revision, e.g. "v2.1" or "origin/main".
+	Ref    string
+	resDir string
+	// the texts of the base resources in the revision
+	baseTexts map[string]string
+}
+
+// Reads the base strings file of the `resDir` in the git revision `ref`.
+func LoadRevision(resDir, baseLocale, stringsFilename, ref string) (*Revision, error) {
+	if err := git.Verify(resDir, ref); err != nil {
+		return nil, err
+	}
+	baseTexts, err := textsAt(resDir, ref, filepath.Join(resDir, resources.ValuesDir(baseLocale), stringsFilename))
+	if err != nil {
+		return nil, err
+	}
+	return &Revision{ref, resDir, baseTexts}, nil
+}
+
+// Returns the texts of the resources of the strings file at `path` in the git revision `ref`,
+// or an empty map if the file did not exist. The files of a file set (see `resources.IsFileSet`)
+// are the ones that exist now.
+func textsAt(resDir, ref, path string) (map[string]string, error) {
+	paths, err := resources.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	var all []*resources.Resources
+	for _, p := range paths {
+		// a file added since the `ref` has no previous resources
+		if data, err := git.Show(resDir, ref, resources.ShortPath(resDir, p)); err == nil {
+			res, err := resources.ParseData(data)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, res)
+		}
+	}
+	if len(all) == 0 {
+		return make(map[string]string), nil
+	}
+	return resources.Override(all).Texts(), nil
+}
+
+// Validates that the translations in the strings file at `path` were updated when their base text changed
+// since the revision: the translations with the same text as in the revision, whose base text is different now,
+// are reported. The resources added to the base or to the translations since then are skipped.
+func (r *Revision) validateStale(res, base *resources.Resources, path, shortPath string) []error {
+	previousTexts, err := textsAt(r.resDir, r.Ref, path)
+	if err != nil {
+		return []error{err}
+	}
+	baseTexts := base.Texts()
+	var errorList []error
+	for name, text := range res.Texts() {
+		previousBaseText, ok := r.baseTexts[name]
+		baseText, exists := baseTexts[name]
+		if !ok || !exists || previousBaseText == baseText {
+			continue
+		}
+		if previousText, ok := previousTexts[name]; ok && previousText == text {
+			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s in %s: The base text changed since %s from '%s' to '%s', but the translation was not updated", name, shortPath, r.Ref, previousBaseText, baseText), shortPath, name, RuleStaleTranslation, nil, ""})
+		}
+	}
+	return errorList
+}
@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"github.com/armatys/android-tools/strings/resources"
+	"sort"
+	"strings"
+)
+
+// The minimal similarity of the base values, for which a translation is suggested.
+const minSuggestionScore = 0.5
+
+// The default minimal confidence of a suggested translation, for which it does not need a review.
+const defaultAutoApproveConfidence = 0.95
+
+// The range of the length ratio (the translation to the base value) considered typical;
+// the confidence of the suggestions outside of it is halved.
+const (
+	minLengthRatio = 0.5
+	maxLengthRatio = 2.0
+)
+
+// A translation suggested for a missing string, taken from a translated string with a similar base value.
+type Suggestion struct {
+	// The name of the string whose translation is suggested.
+	Key string
+	// The base value of that string.
+	Source string
+	// The translation of that string.
+	Translation string
+	// The similarity (0-1) of the base values (see `similarity`).
+	Score float64
+	// True if the translation has the same placeholders as the base value of the missing string.
+	PlaceholdersMatch bool
+	// The length of the translation divided by the length of the base value of the missing string.
+	LengthRatio float64
+	// The confidence (0-1) in the suggestion, combining the score, the placeholders and the length ratio.
+	Confidence float64
+	// True if the confidence is below the `AutoApproveConfidence` of the configuration.
+	NeedsReview bool
+}
+
+// Returns the translation of the string (from `validatedResources`) whose base value is the most similar
+// to the `baseElem` value, or nil if there is no translation similar enough.
+func suggestTranslation(baseElem resources.String, baseResources, validatedResources *resources.Resources, config *Config) *Suggestion {
+	var best *Suggestion
+	for _, translated := range validatedResources.Strings {
+		source := baseResources.FindString(translated.Name)
+		if source == nil || source.Name == baseElem.Name {
+			continue
+		}
+		score := similarity(baseElem.Value, source.Value)
+		if score >= minSuggestionScore && (best == nil || score > best.Score) {
+			best = &Suggestion{Key: translated.Name, Source: source.Value, Translation: translated.Value, Score: score}
+		}
+	}
+	if best != nil {
+		best.score(baseElem.Value, config)
+	}
+	return best
+}
+
+// Scores the suggestion as a translation of the `baseValue`.
+// A suggestion with different placeholders has no confidence, since it would break the formatting.
+func (s *Suggestion) score(baseValue string, config *Config) {
+	s.PlaceholdersMatch = samePlaceholders(baseValue, s.Translation)
+	if baseLength := len([]rune(baseValue)); baseLength > 0 {
+		s.LengthRatio = float64(len([]rune(s.Translation))) / float64(baseLength)
+	}
+	s.Confidence = s.Score
+	if !s.PlaceholdersMatch {
+		s.Confidence = 0
+	}
+	if s.LengthRatio < minLengthRatio || s.LengthRatio > maxLengthRatio {
+		s.Confidence /= 2
+	}
+	s.NeedsReview = s.Confidence < config.autoApproveConfidence()
+}
+
+// The reasons for which a suggestion needs a review (see `ReviewReasons`).
+const (
+	ReviewReasonPlaceholders = "placeholders differ"
+	ReviewReasonLength       = "unusual length"
+	ReviewReasonSimilarity   = "low similarity"
+)
+
+// Returns the reasons for which the suggestion needs a review; empty if it does not need one.
+func (s *Suggestion) ReviewReasons() []string {
+	if !s.NeedsReview {
+		return nil
+	}
+	var reasons []string
+	if !s.PlaceholdersMatch {
+		reasons = append(reasons, ReviewReasonPlaceholders)
+	}
+	if s.LengthRatio < minLengthRatio || s.LengthRatio > maxLengthRatio {
+		reasons = append(reasons, ReviewReasonLength)
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, ReviewReasonSimilarity)
+	}
+	return reasons
+}
+
+// Returns true if `a` and `b` have the same placeholders, regardless of their order.
+func samePlaceholders(a, b string) bool {
+	pa, pb := placeholdersIn(a), placeholdersIn(b)
+	if len(pa) != len(pb) {
+		return false
+	}
+	sort.Strings(pa)
+	sort.Strings(pb)
+	return strings.Join(pa, "\x00") == strings.Join(pb, "\x00")
+}
+
+// Returns the minimal confidence of a suggestion which does not need a review.
+func (c *Config) autoApproveConfidence() float64 {
+	if c == nil || c.AutoApproveConfidence <= 0 {
+		return defaultAutoApproveConfidence
+	}
+	return c.AutoApproveConfidence
+}
@@ -0,0 +1,23 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validates that the value does not have raw tab characters, which are usually pasted from the translation tools;
+// aapt turns them into spaces outside the double quotes, so they render differently than they look in the file.
+// The tabs of the indentation around the value are ignored, and the intended ones should be written as "\t".
+func validateTabCharacters(elemValue string) error {
+	var positions []string
+	for i, r := range []rune(strings.TrimSpace(elemValue)) {
+		if r == '\t' {
+			positions = append(positions, fmt.Sprint(i))
+		}
+	}
+	if len(positions) > 0 {
+		return errors.New(fmt.Sprintf("The value has raw tab character(s) at position(s) %s; use a space or the escaped form (\\t) instead", strings.Join(positions, ", ")))
+	}
+	return nil
+}
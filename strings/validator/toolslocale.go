@@ -0,0 +1,21 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"strings"
+)
+
+// Validates that the tools:locale attribute of the resources in `res` agrees with the locale qualifier
+// of the directory. The attribute may be less specific than the qualifier (e.g. "pt" in "values-pt-rBR").
+func validateToolsLocale(res *resources.Resources, shortPath string) []error {
+	folderLocale := resources.LocaleFromPath(shortPath)
+	if len(res.ToolsLocale) == 0 || !resources.IsLocaleQualifier(folderLocale) {
+		return nil
+	}
+	toolsLocale := resources.LocaleFromLanguageTag(res.ToolsLocale)
+	if strings.EqualFold(toolsLocale, folderLocale) || strings.EqualFold(toolsLocale, languageOf(folderLocale)) {
+		return nil
+	}
+	return []error{&ValidationError{fmt.Sprintf("%s has tools:locale=\"%s\", which does not match the directory locale '%s'", shortPath, res.ToolsLocale, folderLocale), shortPath, "", RuleToolsLocale, nil, ""}}
+}
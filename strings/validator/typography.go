@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Typographic style conventions for a locale.
+type TypographyConfig struct {
+	// If true, requires the ellipsis character "…" instead of three dots "...".
+	Ellipsis bool
+	// The dash required between words (e.g. "–" or "—"); other dashes surrounded by spaces are reported.
+	Dash string
+	// The opening and closing quotation marks (e.g. ["„", "“"] for German); other quotation marks are reported.
+	Quotes []string
+}
+
+// Dashes that are checked when surrounded by spaces.
+var typographyDashes = []string{"-", "–", "—"}
+
+// Quotation marks that are checked; the straight double quote must be escaped in Android strings.
+var typographyQuotes = []string{"\\\"", "“", "”", "„", "‟", "«", "»", "‹", "›", "「", "」"}
+
+// Returns the typography configuration for the `locale`, falling back to the "*" entry.
+func (c *Config) typographyFor(locale string) *TypographyConfig {
+	if c == nil {
+		return nil
+	}
+	if t, ok := c.Typography[locale]; ok {
+		return t
+	}
+	return c.Typography["*"]
+}
+
+// Returns a validation function enforcing the `typography` conventions.
+func typographyValidation(typography *TypographyConfig) simpleValidation {
+	return func(elemValue string) error {
+		var problems []string
+		if typography.Ellipsis && strings.Contains(elemValue, "...") {
+			problems = append(problems, "'...' should be '…'")
+		}
+		if len(typography.Dash) > 0 {
+			for _, dash := range typographyDashes {
+				if dash != typography.Dash && strings.Contains(elemValue, " "+dash+" ") {
+					problems = append(problems, fmt.Sprintf("'%s' should be '%s'", dash, typography.Dash))
+				}
+			}
+		}
+		if len(typography.Quotes) > 0 {
+			for _, quote := range typographyQuotes {
+				if !containsString(typography.Quotes, quote) && strings.Contains(elemValue, quote) {
+					problems = append(problems, fmt.Sprintf("'%s' should be one of %s", quote, strings.Join(typography.Quotes, " ")))
+				}
+			}
+		}
+		if len(problems) > 0 {
+			return errors.New(fmt.Sprintf("Value '%s' does not follow the typographic style: %s", NewLineRegex.ReplaceAllString(elemValue, "\\n"), strings.Join(problems, "; ")))
+		}
+		return nil
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, el := range list {
+		if el == s {
+			return true
+		}
+	}
+	return false
+}
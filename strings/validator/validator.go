@@ -1,56 +1,125 @@
 package validator
 
 import (
-	"encoding/xml"
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"github.com/armatys/android-tools/strings/brands"
+	"github.com/armatys/android-tools/strings/glossary"
+	"github.com/armatys/android-tools/strings/resources"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
-type stringEl struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:",chardata"`
-}
-
-type pluralItemEl struct {
-	Quantity string `xml:"quantity,attr"`
-	Value    string `xml:",chardata"`
+type ResourceMissingError struct {
+	msg string
+	// The short path of the file (e.g. "values-de/strings.xml") in which the resource is missing.
+	Path string
+	// The name of the missing resource.
+	Key string
+	// The translation suggested for the missing string (if `Options.Suggest` is true); may be nil.
+	Suggestion *Suggestion
 }
 
-type pluralEl struct {
-	Name  string         `xml:"name,attr"`
-	Items []pluralItemEl `xml:"item"`
+func (r *ResourceMissingError) Error() string {
+	return r.msg
 }
 
-type stringArrayEl struct {
-	Name  string   `xml:"name,attr"`
-	Items []string `xml:"item"`
+type ValidationError struct {
+	msg string
+	// The short path of the file (e.g. "values-de/strings.xml") with the invalid resource.
+	Path string
+	// The name of the invalid resource.
+	Key string
+	// The ID of the rule that reported the error.
+	Rule string
+	// The position of the resource in the file; nil if unknown (e.g. for an error of a values directory).
+	Position *Position
+	// The suggested replacement of the raw value (see `resources.String.RawValue`) of the string or the item,
+	// for the rules with a mechanical fix (e.g. escaping an apostrophe); empty if there is none.
+	Fix string
 }
 
-type resourcesEl struct {
-	Strings      []stringEl      `xml:"string"`
-	Plurals      []pluralEl      `xml:"plurals"`
-	StringArrays []stringArrayEl `xml:"string-array"`
+func (v *ValidationError) Error() string {
+	return v.msg
 }
 
-type ResourceMissingError struct {
+// Reported when a value references (with @string/name) a string that is not declared in the base resources.
+type DanglingReferenceError struct {
 	msg string
+	// The short path of the file (e.g. "values-de/strings.xml") with the reference.
+	Path string
+	// The name of the string with the reference.
+	Key string
+	// The name of the referenced string.
+	Reference string
+	// The position of the string with the reference in the file; nil if unknown.
+	Position *Position
 }
 
-func (r *ResourceMissingError) Error() string {
-	return r.msg
+func (d *DanglingReferenceError) Error() string {
+	return d.msg
 }
 
-type ValidationError struct {
+// Reported when the validation did not finish before the deadline.
+type DeadlineExceededError struct {
 	msg string
+	// The number of locale files that were not validated.
+	UncheckedCount int
 }
 
-func (v *ValidationError) Error() string {
-	return v.msg
+func (d *DeadlineExceededError) Error() string {
+	return d.msg
 }
 
+// Options for the `Validate` function.
+type Options struct {
+	// If true, reports resources that exist in the base resources, but not in other resources.
+	ShowMissing bool
+	// The time budget for the validation; zero means no limit.
+	// When the deadline passes, the remaining locales are skipped and a `DeadlineExceededError` is reported.
+	Deadline time.Duration
+	// The configuration of the rules and locales; may be nil.
+	Config *Config
+	// The brand variables used in the strings; may be nil.
+	Brands brands.Brands
+	// The approved translations of the project terms; may be nil.
+	Glossary glossary.Glossary
+	// If true, the missing strings are reported with a suggested translation of a similar string.
+	Suggest bool
+	// The res directories of the form-factor overlay modules, keyed by the form factor (e.g. "car").
+	// The overlays in the values directories with a UI mode qualifier (e.g. "values-watch") are found without it.
+	Overlays map[string]string
+	// The res directories of the source sets with a lower priority than the validated one (e.g. "src/main/res"
+	// when validating "src/debug/res"), in the order of their priority. The strings files of the validated res directory
+	// are merged over them the way the Android Gradle plugin merges them, and only the errors of the resources
+	// declared in the validated res directory are reported.
+	SourceSets []string
+	// Limits the validation to the changed files and base strings (e.g. since the git merge base); may be nil.
+	Changes *Changes
+	// How the values directories with configuration qualifiers other than the locale (e.g. "values-night"
+	// or "values-de-v21") are validated: `QualifiersMerge` (the default) or `QualifiersSkip`.
+	Qualifiers string
+	// Glob patterns (e.g. "checkout_*") limiting the validation to the resources with the matching names;
+	// all resources are validated if empty.
+	Keys []string
+	// The strings files in a past git revision, with which the translations are compared to find the stale ones; may be nil.
+	Since *Revision
+}
+
+// The handling of the values directories with configuration qualifiers (see `Options.Qualifiers`).
+const (
+	// The directories are validated as overlays of the directories without the qualifiers, e.g. "values-de-night"
+	// is compared with "values-night" merged over the base resources.
+	QualifiersMerge = "merge"
+	// The directories are not validated.
+	QualifiersSkip = "skip"
+)
+
 // A type of function that validates the `validatedString` based on the `baseString`.
 type comparisonValidation func(baseString, validatedString string) error
 
@@ -65,219 +134,509 @@ var NewLineRegex *regexp.Regexp = regexp.MustCompile("(\n)")
 // Validate the string resources that are inside the "resDir" directory.
 // The XML string file for the "baseLocale" is not validated, but used for comparison.
 // Returns a list of errors.
-func Validate(resDir, baseLocale, stringsFilename string, showMissing bool) (errorList []error) {
+func Validate(resDir, baseLocale, stringsFilename string, options Options) []error {
+	return validate(context.Background(), resDir, baseLocale, stringsFilename, options)
+}
+
+// Validates like `Validate`; when the `ctx` is done, the remaining locales are skipped
+// and a `DeadlineExceededError` is reported.
+func validate(ctx context.Context, resDir, baseLocale, stringsFilename string, options Options) (errorList []error) {
+	startTime := time.Now()
 	errorList = make([]error, 0)
-	baseResources, err := parseResources(resDir, baseLocale, stringsFilename)
+	parse := sourceSetParser(resDir, options.SourceSets)
+	baseResources, err := parse(filepath.Join(resDir, resources.ValuesDir(baseLocale), stringsFilename))
 	if err != nil {
 		errorList = append(errorList, err)
 		return
 	}
 
-	paths, err := getOtherStringsFilePaths(resDir, baseLocale, stringsFilename)
+	allPaths, err := sourceSetLocalePaths(resDir, baseLocale, stringsFilename, options.SourceSets)
 	if err != nil {
 		errorList = append(errorList, err)
 		return
 	}
+	basePath := filepath.Join(resources.ValuesDir(baseLocale), stringsFilename)
+	validateBrands := options.Brands != nil && options.Config.IsRuleEnabled(RuleBrandVariables)
+	var baseErrors []error
+	if validateBrands {
+		baseErrors = append(baseErrors, validateBrandVariables(baseResources, basePath, options.Brands)...)
+	}
+	if options.Config.IsRuleEnabled(RuleStringReuse) {
+		var threshold float64
+		if options.Config != nil {
+			threshold = options.Config.ReuseThreshold
+		}
+		baseErrors = append(baseErrors, validateStringReuse(baseResources, basePath, threshold)...)
+	}
+	baseErrors = append(baseErrors, validateCustomRules(baseResources, basePath, options.Config, true)...)
+	if options.Config.IsRuleEnabled(RuleToolsLocale) {
+		baseErrors = append(baseErrors, validateToolsLocale(baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RulePercentSafety) {
+		baseErrors = append(baseErrors, validatePercentSafety(baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RulePluralOther) {
+		baseErrors = append(baseErrors, validatePluralOther(baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RulePlaceholderIndexing) {
+		baseErrors = append(baseErrors, validatePlaceholderIndexing(baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RuleStringReference) {
+		baseErrors = append(baseErrors, validateStringReferences(baseResources, baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RuleMaxLength) {
+		baseErrors = append(baseErrors, validateMaxLength(baseResources, baseResources, basePath, options.Config)...)
+	}
+	if options.Config.IsRuleEnabled(RuleNormalization) {
+		baseErrors = append(baseErrors, validateNormalization(baseResources, basePath)...)
+	}
+	if options.Config.IsRuleEnabled(RuleDuplicateName) && isFile(filepath.Join(resDir, basePath)) {
+		baseErrors = append(baseErrors, validateDuplicateNames(filepath.Join(resDir, basePath), basePath)...)
+		errorList = append(errorList, validateCrossFileDuplicates(resDir, options.Config)...)
+	}
+	if options.Config.IsRuleEnabled(RuleSortedNames) {
+		baseErrors = append(baseErrors, validateSortedNames(filepath.Join(resDir, basePath), basePath)...)
+	}
+	errorList = append(errorList, withoutIgnored(baseErrors, baseResources)...)
+	if options.Config.IsRuleEnabled(RuleLocaleDirectory) {
+		errorList = append(errorList, validateLocaleDirectories(resDir)...)
+	}
 
+	var paths []string
+	overlays := make(map[string]*overlay)
+	for _, path := range allPaths {
+		q, err := resources.ParseQualifiers(filepath.Base(filepath.Dir(path)))
+		if err == nil && len(q.Config) > 0 {
+			// a configuration-only directory (e.g. "values-night") or a locale with other qualifiers (e.g. "values-de-v21")
+			if options.Qualifiers == QualifiersSkip {
+				continue
+			}
+			locale := q.Locale
+			if locale == baseLocale {
+				locale = ""
+			}
+			name := strings.Join(q.Config, "-")
+			if _, ok := overlays[name]; !ok {
+				overlays[name] = newOverlay(q.Config)
+			}
+			overlays[name].paths[locale] = path
+		} else if resources.IsPseudoLocale(resources.LocaleFromPath(path)) {
+			// generated from the base strings, e.g. by the 'pseudo' action
+			continue
+		} else if options.Config.IsLocaleIncluded(resources.ShortPath(resDir, path)) {
+			paths = append(paths, path)
+		}
+	}
+	for formFactor, moduleDir := range options.Overlays {
+		modulePaths, err := resources.OtherLocalePaths(moduleDir, baseLocale, stringsFilename)
+		if err != nil {
+			errorList = append(errorList, err)
+			continue
+		}
+		o := &overlay{[]string{formFactor}, fmt.Sprintf("the %s form factor", formFactor), moduleDir, make(map[string]string)}
+		if basePath := filepath.Join(moduleDir, resources.ValuesDir(baseLocale), stringsFilename); isFile(basePath) {
+			o.paths[""] = basePath
+		}
+		for _, path := range modulePaths {
+			if locale := resources.LocaleFromPath(path); resources.IsLocaleQualifier(locale) {
+				o.paths[locale] = path
+			}
+		}
+		overlays[formFactor] = o
+	}
+	var translatedLocales []string
 	for _, path := range paths {
-		resources, err := parseResourcesFile(path)
+		if locale := resources.LocaleFromPath(path); resources.IsLocaleQualifier(locale) {
+			translatedLocales = append(translatedLocales, locale)
+		}
+	}
+	var overlayNames []string
+	for name := range overlays {
+		overlayNames = append(overlayNames, name)
+	}
+	sort.Strings(overlayNames)
+	for _, name := range overlayNames {
+		errorList = append(errorList, validateOverlay(resDir, stringsFilename, overlays[name], baseResources, translatedLocales, options, parse)...)
+	}
+
+	var deadlineError error
+	for i, path := range paths {
+		if (options.Deadline > 0 && time.Since(startTime) > options.Deadline) || ctx.Err() != nil {
+			uncheckedCount := len(paths) - i
+			deadlineError = &DeadlineExceededError{fmt.Sprintf("[deadline] %d locale(s) not checked", uncheckedCount), uncheckedCount}
+			break
+		}
+
+		shortPath := resources.ShortPath(resDir, path)
+		if options.Changes != nil && len(options.Changes.Keys) == 0 && !options.Changes.includes(shortPath) {
+			continue
+		}
+		validatedResources, err := parse(path)
 		if err != nil {
 			errorList = append(errorList, err)
 			continue
 		}
 
-		shortPath := extractShortPath(resDir, path)
-		ers := validateResources(baseResources, resources, shortPath, showMissing)
-		errorList = append(errorList, ers...)
+		ers := validateResources(baseResources, validatedResources, shortPath, options)
+		if validateBrands {
+			ers = append(ers, validateBrandVariables(validatedResources, shortPath, options.Brands)...)
+		}
+		ers = append(ers, validateCustomRules(validatedResources, shortPath, options.Config, false)...)
+		if options.Config.IsRuleEnabled(RuleToolsLocale) {
+			ers = append(ers, validateToolsLocale(validatedResources, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RulePercentSafety) {
+			ers = append(ers, validatePercentSafety(validatedResources, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RulePluralOther) {
+			ers = append(ers, validatePluralOther(validatedResources, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RulePlaceholderIndexing) {
+			ers = append(ers, validatePlaceholderIndexing(validatedResources, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RuleStringReference) {
+			ers = append(ers, validateStringReferences(validatedResources, baseResources, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RuleMaxLength) {
+			ers = append(ers, validateMaxLength(validatedResources, baseResources, shortPath, options.Config)...)
+		}
+		if options.Config.IsRuleEnabled(RuleNormalization) {
+			ers = append(ers, validateNormalization(validatedResources, shortPath)...)
+		}
+		if options.Config != nil && options.Config.Spelling != nil && options.Config.IsRuleEnabled(RuleSpelling) {
+			ers = append(ers, validateSpelling(validatedResources, shortPath, validatedResources.ResolveLocale(shortPath), options.Config.Spelling)...)
+		}
+		if options.Config.IsRuleEnabled(RuleDuplicateName) && isFile(path) {
+			ers = append(ers, validateDuplicateNames(path, shortPath)...)
+		}
+		if options.Config.IsRuleEnabled(RuleSortedNames) {
+			ers = append(ers, validateSortedNames(path, shortPath)...)
+		}
+		if options.Since != nil && options.Config.IsRuleEnabled(RuleStaleTranslation) {
+			ers = append(ers, options.Since.validateStale(validatedResources, baseResources, path, shortPath)...)
+		}
+		errorList = append(errorList, withoutIgnored(ers, baseResources, validatedResources)...)
 	}
 
+	if len(options.SourceSets) > 0 {
+		errorList = declaredInSourceSet(errorList, resDir, baseLocale, stringsFilename)
+	}
+	if options.Changes != nil {
+		errorList = options.Changes.filter(errorList)
+	}
+	if len(options.Keys) > 0 {
+		errorList = withKeys(errorList, options.Keys)
+	}
+	errorList = withoutIgnoredNames(errorList, options.Config)
+	setPositions(errorList, resDir)
+	sortErrors(errorList)
+	if deadlineError != nil {
+		errorList = append(errorList, deadlineError)
+	}
 	return
 }
 
-func valuesDir(locale string) string {
-	if len(locale) > 0 {
-		return fmt.Sprintf("values-%s", locale)
+// Returns true if there is a file at `path`, or any file of the file set at `path` (see `resources.IsFileSet`).
+func isFile(path string) bool {
+	if resources.IsFileSet(filepath.Base(path)) {
+		paths, err := resources.ExpandPath(path)
+		return err == nil && len(paths) > 0
 	}
-	return "values"
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
 }
 
-// Constructs the file path from `resDir`, `localeName` and `stringsFilename`,
-// and returns parsed resources or an error.
-func parseResources(resDir, localeName, stringsFilename string) (*resourcesEl, error) {
-	var path string = filepath.Join(resDir, valuesDir(localeName), stringsFilename)
-	return parseResourcesFile(path)
+// Finds the item of the `basePlural` corresponding to the `quantity`.
+// If the base plural does not declare that quantity (e.g. "few" in Polish), the "other" item is returned.
+func findBasePluralItem(basePlural *resources.Plural, quantity string) *resources.PluralItem {
+	if item := basePlural.FindItem(quantity); item != nil {
+		return item
+	}
+	return basePlural.FindItem("other")
 }
 
-// Reads a file at a `path` and returns parsed resources object, or an error.
-func parseResourcesFile(path string) (*resourcesEl, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	var resources resourcesEl
-	err = xml.Unmarshal([]byte(data), &resources)
-	if err != nil {
-		return nil, err
+// Returns the errors, except the ones whose rule is suppressed for the resource in any of the `res`
+// (see `resources.Resources.IsIgnored`).
+func withoutIgnored(errorList []error, res ...*resources.Resources) []error {
+	var filtered []error
+	for _, err := range errorList {
+		_, key, rule := errorLocation(err)
+		ignored := false
+		for _, r := range res {
+			if len(rule) > 0 && r.IsIgnored(key, rule) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			filtered = append(filtered, err)
+		}
 	}
-	return &resources, nil
+	return filtered
 }
 
-// Generates the file paths for other string resource files.
-// `resDir` is the path to the Android's "res" directory.
-// `exceptForLocale` is the locale of the file path, that will not be included in the returned paths.
-// `stringsFilename` is the name of the XML file that contains the string resources (e.g. "strings.xml").
-func getOtherStringsFilePaths(resDir, exceptForLocale, stringsFilename string) ([]string, error) {
-	patt := filepath.Join(resDir, "values-*", stringsFilename)
-	paths, err := filepath.Glob(patt)
-	if err != nil {
-		return nil, err
-	}
-	patt2 := filepath.Join(resDir, "values", stringsFilename)
-	paths2, err := filepath.Glob(patt2)
-	if err != nil {
-		return nil, err
+// Returns the file path, the resource name and the rule ID, which the error refers to.
+// For other errors (e.g. I/O errors) returns empty strings.
+func errorLocation(err error) (path, key, rule string) {
+	switch e := err.(type) {
+	case *ValidationError:
+		return e.Path, e.Key, e.Rule
+	case *ResourceMissingError:
+		return e.Path, e.Key, RuleMissing
+	case *DanglingReferenceError:
+		return e.Path, e.Key, RuleStringReference
 	}
-	paths = append(paths, paths2...)
-	exceptForPath := filepath.Join(resDir, valuesDir(exceptForLocale), stringsFilename)
+	return "", "", ""
+}
 
-	idx := -1
-	for i, p := range paths {
-		if p == exceptForPath {
-			idx = i
-			break
+// Sorts the errors by the file path (i.e. by the locale), then by the resource name, the rule and the message,
+// so that the output is the same between runs regardless of the order in which the errors were found.
+// The errors without a file (e.g. I/O errors) go first.
+func sortErrors(errorList []error) {
+	sort.SliceStable(errorList, func(i, j int) bool {
+		pathI, keyI, ruleI := errorLocation(errorList[i])
+		pathJ, keyJ, ruleJ := errorLocation(errorList[j])
+		if pathI != pathJ {
+			return pathI < pathJ
 		}
-	}
+		if keyI != keyJ {
+			return keyI < keyJ
+		}
+		if ruleI != ruleJ {
+			return ruleI < ruleJ
+		}
+		return errorList[i].Error() < errorList[j].Error()
+	})
+}
 
-	if idx >= 0 {
-		paths = append(paths[:idx], paths[idx+1:]...)
-	}
+// A validation rule that compares the validated value with the base value.
+type comparisonRule struct {
+	id string
+	fn comparisonValidation
+	// If true, the rule is given the raw values (inner XML, including the markup) instead of the text content.
+	raw bool
+}
 
-	return paths, nil
+// A validation rule that checks the validated value on its own.
+type simpleRule struct {
+	id string
+	fn simpleValidation
 }
 
-func findStringElement(resources *resourcesEl, name string) *stringEl {
-	for _, el := range resources.Strings {
-		if el.Name == name {
-			return &el
-		}
-	}
-	return nil
+var comparisonRules = []comparisonRule{
+	{RuleSimplePlaceholders, validateSimplePlaceholders, false},
+	{RulePositionalPlaceholders, validatePositionalPlaceholders, false},
+	{RuleMarkup, validateMarkup, true},
+	{RuleXliff, validateXliffPlaceholders, true},
+	{RuleEmptyTranslation, validateEmptyTranslation, false},
+	{RuleWhitespace, validateWhitespace, false},
+	{RuleEscapeParity, validateEscapeParity, false},
+	{RuleLinks, validateLinks, true},
+	{RuleNumbers, validateNumbers, false},
 }
 
-func findStringArrayElement(resources *resourcesEl, name string) *stringArrayEl {
-	for _, el := range resources.StringArrays {
-		if el.Name == name {
-			return &el
-		}
-	}
-	return nil
+// The rules that check the format placeholders; they are skipped for the strings marked with formatted="false".
+var placeholderRules = map[string]bool{
+	RuleSimplePlaceholders:     true,
+	RulePositionalPlaceholders: true,
+	RulePotentialPlaceholder:   true,
+	RuleIOSSpecifiers:          true,
+	RuleBarePercent:            true,
 }
 
-// Extracts the short path for a string file (e.g. "values-en/strings.xml")
-// based on the `resDir` path and the `stringsFilePath`.
-// If extraction fails, it returns `stringsFilePath`.
-func extractShortPath(resDir, stringsFilePath string) string {
-	p, err := filepath.Rel(resDir, stringsFilePath)
-	if err != nil {
-		p = stringsFilePath
-	}
-	return p
+// The rules given the values as aapt compiles them (e.g. without the enclosing double quotes, which keep the whitespace),
+// since they check what is shown in the app rather than how it is written in the file.
+var compiledValueRules = map[string]bool{
+	RuleSimplePlaceholders:     true,
+	RulePositionalPlaceholders: true,
+	RuleWhitespace:             true,
+}
+
+var simpleRules = []simpleRule{
+	{RulePotentialPlaceholder, validatePotentialPlaceholder},
+	{RuleNewline, validateNewlineCharacters},
+	{RuleUnescapedQuotes, validateQuotesEscaping},
+	{RuleIOSSpecifiers, validateIOSSpecifiers},
+	{RuleBarePercent, validateBarePercent},
+	{RuleInvisibleCharacters, validateInvisibleCharacters},
+	{RuleTabCharacters, validateTabCharacters},
 }
 
 // Validates the resources against the `baseResources`, which are expected to contain no errors.
 // Returns a list of validation errors.
-// If `showMissing` is true, this function returns an error
+// If `options.ShowMissing` is true, this function returns an error
 // when a resource exists in the `baseResources`, but not in `validatedResources`.
-func validateResources(baseResources, validatedResources *resourcesEl, shortPath string, showMissing bool) []error {
+func validateResources(baseResources, validatedResources *resources.Resources, shortPath string, options Options) []error {
 	var errorList []error
+	config := options.Config
+	showMissing := options.ShowMissing && config.IsRuleEnabled(RuleMissing)
 
-	for _, validatedElem := range validatedResources.Strings {
-		hasBaseValue := false
-		for _, baseElem := range baseResources.Strings {
-			if validatedElem.Name == baseElem.Name {
-				hasBaseValue = true
-				break
+	if config.IsRuleEnabled(RuleNoBaseValue) {
+		for _, validatedElem := range validatedResources.Strings {
+			if baseResources.FindString(validatedElem.Name) == nil {
+				valError := ValidationError{fmt.Sprintf("%s in %s does not have a base value.", validatedElem.Name, shortPath), shortPath, validatedElem.Name, RuleNoBaseValue, nil, ""}
+				errorList = append(errorList, &valError)
 			}
 		}
-		if !hasBaseValue {
-			valError := ValidationError{fmt.Sprintf("%s in %s does not have a base value.", validatedElem.Name, shortPath)}
-			errorList = append(errorList, &valError)
+		for _, validatedElem := range validatedResources.StringArrays {
+			if baseResources.FindStringArray(validatedElem.Name) == nil {
+				valError := ValidationError{fmt.Sprintf("%s array in %s does not have a base value.", validatedElem.Name, shortPath), shortPath, validatedElem.Name, RuleNoBaseValue, nil, ""}
+				errorList = append(errorList, &valError)
+			}
 		}
 	}
 
-	comparisonValidationFuncs := []comparisonValidation{validateSimplePlaceholders, validatePositionalPlaceholders}
-	simpleValidationFuncs := []simpleValidation{validatePotentialPlaceholder, validateNewlineCharacters}
+	pipeline := newValuePipeline(validatedResources, shortPath, config, options.Glossary)
 
 	// Validate string elements
 	for _, baseElem := range baseResources.Strings {
-		validatedElem := findStringElement(validatedResources, baseElem.Name)
-		if validatedElem == nil {
-			if showMissing {
-				errorList = append(errorList, &ResourceMissingError{fmt.Sprintf("[missing] element named %s in %s", baseElem.Name, shortPath)})
+		validatedElem := validatedResources.FindString(baseElem.Name)
+		if !baseElem.IsTranslatable() {
+			if validatedElem != nil && config.IsRuleEnabled(RuleNonTranslatable) {
+				errorList = append(errorList, nonTranslatableError(baseElem.Name, shortPath))
 			}
 			continue
 		}
-		for _, fn := range comparisonValidationFuncs {
-			if err := fn(baseElem.Value, validatedElem.Value); err != nil {
-				valError := ValidationError{fmt.Sprintf("%s in %s: %s", baseElem.Name, shortPath, err.Error())}
-				errorList = append(errorList, &valError)
+		if validatedElem == nil {
+			// A reference resolves to the translation of the referenced string.
+			if showMissing && len(baseElem.Reference()) == 0 {
+				missingError := missingResourceError(baseElem.Name, shortPath)
+				if options.Suggest {
+					missingError.Suggestion = suggestTranslation(baseElem, baseResources, validatedResources, config)
+				}
+				errorList = append(errorList, missingError)
 			}
+			continue
 		}
-		for _, fn := range simpleValidationFuncs {
-			if err := fn(validatedElem.Value); err != nil {
-				valError := ValidationError{fmt.Sprintf("%s in %s: %s", baseElem.Name, shortPath, err.Error())}
-				errorList = append(errorList, &valError)
-			}
+		if len(baseElem.Reference()) > 0 || len(validatedElem.Reference()) > 0 {
+			// The referenced strings are validated on their own.
+			continue
 		}
+		errorList = append(errorList, pipeline.validate(pipelineValue{
+			name:      baseElem.Name,
+			label:     baseElem.Name,
+			value:     ruleValue{validatedElem.Value, validatedElem.CompiledValue, validatedElem.RawValue},
+			base:      &ruleValue{baseElem.Value, baseElem.CompiledValue, baseElem.RawValue},
+			formatted: baseElem.IsFormatted() && validatedElem.IsFormatted(),
+		})...)
 	}
 
 	// Validate string-array elements
 	for _, baseElem := range baseResources.StringArrays {
-		validatedElem := findStringArrayElement(validatedResources, baseElem.Name)
+		validatedElem := validatedResources.FindStringArray(baseElem.Name)
+		if !baseElem.IsTranslatable() {
+			if validatedElem != nil && config.IsRuleEnabled(RuleNonTranslatable) {
+				errorList = append(errorList, nonTranslatableError(baseElem.Name, shortPath))
+			}
+			continue
+		}
 		if validatedElem == nil {
 			if showMissing {
-				errorList = append(errorList, &ResourceMissingError{fmt.Sprintf("[missing] element named %s in %s", baseElem.Name, shortPath)})
+				errorList = append(errorList, missingResourceError(baseElem.Name, shortPath))
 			}
 			continue
 		}
 		if len(baseElem.Items) != len(validatedElem.Items) {
-			errorList = append(errorList, &ValidationError{fmt.Sprintf("%s array in %s has %d items, but it should have %d", validatedElem.Name, shortPath, len(validatedElem.Items), len(baseElem.Items))})
+			if config.IsRuleEnabled(RuleArraySize) {
+				errorList = append(errorList, &ValidationError{fmt.Sprintf("%s array in %s has %d items, but it should have %d", validatedElem.Name, shortPath, len(validatedElem.Items), len(baseElem.Items)), shortPath, validatedElem.Name, RuleArraySize, nil, ""})
+			}
 			continue
 		}
 		for i := range baseElem.Items {
-			for _, fn := range comparisonValidationFuncs {
-				if err := fn(baseElem.Items[i], validatedElem.Items[i]); err != nil {
-					valError := ValidationError{fmt.Sprintf("%s in %s: %s", baseElem.Name, shortPath, err.Error())}
-					errorList = append(errorList, &valError)
-				}
+			// The items referencing the strings are compared by the values of the referenced strings.
+			baseItem, baseTarget, baseResolved := resolveArrayItem(baseElem.Items[i], baseResources, baseResources)
+			validatedItem, target, resolved := resolveArrayItem(validatedElem.Items[i], validatedResources, baseResources)
+			if !baseResolved || !resolved {
+				// The dangling and circular references are reported by the "string-reference" rule.
+				continue
 			}
-			for _, fn := range simpleValidationFuncs {
-				if err := fn(validatedElem.Items[i]); err != nil {
-					valError := ValidationError{fmt.Sprintf("%s in %s: %s", baseElem.Name, shortPath, err.Error())}
-					errorList = append(errorList, &valError)
-				}
+			if len(target) > 0 && target == baseTarget {
+				// The referenced string is validated on its own.
+				continue
 			}
+			errorList = append(errorList, pipeline.validate(pipelineValue{
+				name:       baseElem.Name,
+				label:      baseElem.Name,
+				value:      ruleValue{validatedItem.Value, validatedItem.CompiledValue, validatedItem.RawValue},
+				base:       &ruleValue{baseItem.Value, baseItem.CompiledValue, baseItem.RawValue},
+				formatted:  true,
+				referenced: len(target) > 0,
+			})...)
 		}
 	}
 
 	// Validate plurals elements
 	for _, pluralsElem := range validatedResources.Plurals {
+		baseElem := baseResources.FindPlural(pluralsElem.Name)
+		if baseElem == nil && config.IsRuleEnabled(RuleNoBaseValue) {
+			valError := ValidationError{fmt.Sprintf("%s plurals in %s does not have a base value.", pluralsElem.Name, shortPath), shortPath, pluralsElem.Name, RuleNoBaseValue, nil, ""}
+			errorList = append(errorList, &valError)
+		}
+		if baseElem != nil && !baseElem.IsTranslatable() {
+			if config.IsRuleEnabled(RuleNonTranslatable) {
+				errorList = append(errorList, nonTranslatableError(pluralsElem.Name, shortPath))
+			}
+			continue
+		}
 		for _, pluralValue := range pluralsElem.Items {
-			for _, fn := range simpleValidationFuncs {
-				if err := fn(pluralValue.Value); err != nil {
-					valError := ValidationError{fmt.Sprintf("%s in %s: %s", pluralsElem.Name, shortPath, err.Error())}
-					errorList = append(errorList, &valError)
+			v := pipelineValue{
+				name:      pluralsElem.Name,
+				label:     fmt.Sprintf("%s (%s)", pluralsElem.Name, pluralValue.Quantity),
+				value:     ruleValue{pluralValue.Value, pluralValue.CompiledValue, pluralValue.RawValue},
+				formatted: true,
+			}
+			if baseElem != nil {
+				// Since the languages have different plural rules (e.g. "one" in Russian also matches 21),
+				// the item is also accepted if it matches the base "other" item.
+				if baseItem := findBasePluralItem(baseElem, pluralValue.Quantity); baseItem != nil {
+					v.base = &ruleValue{baseItem.Value, baseItem.CompiledValue, baseItem.RawValue}
+					if otherItem := baseElem.FindItem("other"); otherItem != nil && baseItem.Quantity != "other" {
+						v.alternativeBase = &ruleValue{otherItem.Value, otherItem.CompiledValue, otherItem.RawValue}
+					}
 				}
 			}
+			errorList = append(errorList, pipeline.validate(v)...)
+		}
+	}
+
+	if showMissing {
+		for _, baseElem := range baseResources.Plurals {
+			if baseElem.IsTranslatable() && validatedResources.FindPlural(baseElem.Name) == nil {
+				errorList = append(errorList, missingResourceError(baseElem.Name, shortPath))
+			}
 		}
 	}
 
+	if config.IsRuleEnabled(RulePluralQuantities) {
+		errorList = append(errorList, validatePluralQuantities(validatedResources, baseResources, validatedResources.ResolveLocale(shortPath), shortPath)...)
+	}
+
+	if config != nil && config.Casing != nil && config.IsRuleEnabled(RuleCasing) {
+		errorList = append(errorList, validateCasingOfResources(baseResources, validatedResources, validatedResources.ResolveLocale(shortPath), shortPath, config.Casing)...)
+	}
+
 	return errorList
 }
 
+func missingResourceError(name, shortPath string) *ResourceMissingError {
+	return &ResourceMissingError{fmt.Sprintf("[missing] element named %s in %s", name, shortPath), shortPath, name, nil}
+}
+
+func nonTranslatableError(name, shortPath string) error {
+	return &ValidationError{fmt.Sprintf("%s in %s is marked as translatable=\"false\" in the base resources, but it is translated.", name, shortPath), shortPath, name, RuleNonTranslatable, nil, ""}
+}
+
+// Validates that the `validatedElemString` is not empty (or whitespace-only), unless the `baseElemString` is empty as well.
+func validateEmptyTranslation(baseElemString, validatedElemString string) error {
+	if len(strings.TrimSpace(resources.UnescapeValue(validatedElemString))) == 0 && len(strings.TrimSpace(resources.UnescapeValue(baseElemString))) > 0 {
+		return errors.New("The target string is empty, while the base string is not")
+	}
+	return nil
+}
+
 func validateSimplePlaceholders(baseElemString, validatedElemString string) error {
-	baseMatches := SimplePlaceholderRegex.FindAllStringSubmatch(baseElemString, -1)
-	targetMatches := SimplePlaceholderRegex.FindAllStringSubmatch(validatedElemString, -1)
+	baseMatches := SimplePlaceholderRegex.FindAllStringSubmatch(withoutEscapedPercents(baseElemString), -1)
+	targetMatches := SimplePlaceholderRegex.FindAllStringSubmatch(withoutEscapedPercents(validatedElemString), -1)
 	baseMatchesCount := len(baseMatches)
 	targetMatchesCount := len(targetMatches)
 	if baseMatchesCount == 0 && targetMatchesCount == 0 {
@@ -295,33 +654,65 @@ func validateSimplePlaceholders(baseElemString, validatedElemString string) erro
 	return nil
 }
 
+// The argument types of the format conversions, e.g. both "d" and "x" format an integer.
+var conversionTypes = map[string]string{
+	"d": "integer", "o": "integer", "x": "integer", "X": "integer",
+	"e": "float", "E": "float", "f": "float", "g": "float", "G": "float", "a": "float", "A": "float",
+	"s": "string", "S": "string", "b": "boolean", "B": "boolean", "c": "character", "C": "character",
+	"h": "hash", "H": "hash", "t": "date", "T": "date",
+}
+
+// Returns the type of the argument formatted with the `conversion`, or the conversion itself if it is unknown.
+func conversionType(conversion string) string {
+	if t, ok := conversionTypes[conversion]; ok {
+		return t
+	}
+	return conversion
+}
+
+// Splits the positional placeholder (e.g. "%1$d") into the argument index ("1") and the conversion ("d").
+func splitPositionalPlaceholder(placeholder string) (index, conversion string) {
+	parts := strings.SplitN(strings.TrimPrefix(placeholder, "%"), "$", 2)
+	return parts[0], parts[1]
+}
+
 func validatePositionalPlaceholders(baseElemString, validatedElemString string) error {
-	baseMatches := PositionalPlaceholderRegex.FindAllStringSubmatch(baseElemString, -1)
-	targetMatches := PositionalPlaceholderRegex.FindAllStringSubmatch(validatedElemString, -1)
+	baseMatches := PositionalPlaceholderRegex.FindAllStringSubmatch(withoutEscapedPercents(baseElemString), -1)
+	targetMatches := PositionalPlaceholderRegex.FindAllStringSubmatch(withoutEscapedPercents(validatedElemString), -1)
 	baseMatchesCount := len(baseMatches)
 	targetMatchesCount := len(targetMatches)
 	if baseMatchesCount == 0 && targetMatchesCount == 0 {
 		return nil
 	}
-	if baseMatchesCount != targetMatchesCount {
-		return errors.New(fmt.Sprintf("The target string has %d placeholder(s), while it should probably have %d", targetMatchesCount, baseMatchesCount))
+
+	baseConversions := make(map[string]string)
+	for _, match := range baseMatches {
+		index, conversion := splitPositionalPlaceholder(match[1])
+		baseConversions[index] = conversion
 	}
-	for i, match := range baseMatches {
-		var foundMatch []string = nil
-		for _, tmatch := range targetMatches {
-			if match[1] == tmatch[1] {
-				foundMatch = tmatch
-			}
+	targetConversions := make(map[string]string)
+	for _, match := range targetMatches {
+		index, conversion := splitPositionalPlaceholder(match[1])
+		if baseConversion, ok := baseConversions[index]; !ok {
+			return errors.New(fmt.Sprintf("The target string has the placeholder %s for the argument #%s, which is not used in the base string", match[1], index))
+		} else if conversionType(conversion) != conversionType(baseConversion) {
+			return errors.New(fmt.Sprintf("The argument #%s changed type from %s (%%%s$%s) to %s (%s)", index, conversionType(baseConversion), index, baseConversion, conversionType(conversion), match[1]))
 		}
-		if foundMatch == nil {
-			return errors.New(fmt.Sprintf("The target string placeholder #%d is %s, while it probably should be %s", i, foundMatch[1], match[1]))
+		targetConversions[index] = conversion
+	}
+	for i, match := range baseMatches {
+		if index, _ := splitPositionalPlaceholder(match[1]); len(targetConversions[index]) == 0 {
+			return errors.New(fmt.Sprintf("The target string does not have the placeholder #%d %s", i, match[1]))
 		}
 	}
+	if baseMatchesCount != targetMatchesCount {
+		return errors.New(fmt.Sprintf("The target string has %d placeholder(s), while it should probably have %d", targetMatchesCount, baseMatchesCount))
+	}
 	return nil
 }
 
 func validatePotentialPlaceholder(elemValue string) error {
-	matches := PotentialPlaceholderRegex.FindAllStringSubmatch(elemValue, -1)
+	matches := PotentialPlaceholderRegex.FindAllStringSubmatch(withoutEscapedPercents(elemValue), -1)
 	if len(matches) > 0 {
 		return errors.New(fmt.Sprintf("Value '%s' has a potential placeholder", NewLineRegex.ReplaceAllString(elemValue, "\\n")))
 	}
@@ -335,3 +726,35 @@ func validateNewlineCharacters(elemValue string) error {
 	}
 	return nil
 }
+
+// Validates that apostrophes are escaped (\') and double quotes are either escaped (\") or paired,
+// since an unescaped apostrophe breaks the aapt compilation, and an unpaired double quote
+// is silently dropped. Apostrophes inside a double-quoted part of the string are allowed.
+func validateQuotesEscaping(elemValue string) error {
+	escaped := false
+	inQuotes := false
+	lastQuotePosition := 0
+	position := 0
+	for _, r := range elemValue {
+		position += 1
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '"':
+			inQuotes = !inQuotes
+			lastQuotePosition = position
+		case '\'':
+			if !inQuotes {
+				return errors.New(fmt.Sprintf("Value '%s' has an unescaped apostrophe at position %d", NewLineRegex.ReplaceAllString(elemValue, "\\n"), position))
+			}
+		}
+	}
+	if inQuotes {
+		return errors.New(fmt.Sprintf("Value '%s' has an unescaped double quote at position %d", NewLineRegex.ReplaceAllString(elemValue, "\\n"), lastQuotePosition))
+	}
+	return nil
+}
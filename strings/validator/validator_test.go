@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortErrors(t *testing.T) {
+	at := func(line int) *Position {
+		return &Position{"", line, 5}
+	}
+	tests := []struct {
+		name      string
+		errorList []error
+		want      []string
+	}{
+		{
+			"by the file",
+			[]error{
+				&ValidationError{"b", "values-pl/strings.xml", "a", RuleNewline, at(3), ""},
+				&ValidationError{"a", "values-de/strings.xml", "z", RuleNewline, at(9), ""},
+			},
+			[]string{"a", "b"},
+		},
+		{
+			"by the key within a file, regardless of the line",
+			[]error{
+				&ValidationError{"z at line 3", "values-de/strings.xml", "z_last_by_name", RuleNewline, at(3), ""},
+				&ValidationError{"a at line 7", "values-de/strings.xml", "a_first_by_name", RuleNewline, at(7), ""},
+			},
+			[]string{"a at line 7", "z at line 3"},
+		},
+		{
+			"by the rule for the same key",
+			[]error{
+				&ValidationError{"whitespace", "values-de/strings.xml", "a", RuleWhitespace, at(3), ""},
+				&ValidationError{"markup", "values-de/strings.xml", "a", RuleMarkup, at(3), ""},
+				&ResourceMissingError{"missing", "values-de/strings.xml", "b", nil},
+			},
+			[]string{"markup", "whitespace", "missing"},
+		},
+		{
+			"the errors without a file first",
+			[]error{
+				&ValidationError{"positioned", "values-de/strings.xml", "a", RuleNewline, at(3), ""},
+				errors.New("read error"),
+			},
+			[]string{"read error", "positioned"},
+		},
+		{
+			"by the key, then the rule and the message",
+			[]error{
+				&ResourceMissingError{"missing b", "values-de/strings.xml", "b", nil},
+				&ResourceMissingError{"missing a", "values-de/strings.xml", "a", nil},
+				&ValidationError{"y", "values-de/strings.xml", "a", RuleNoBaseValue, nil, ""},
+				&ValidationError{"x", "values-de/strings.xml", "a", RuleNoBaseValue, nil, ""},
+			},
+			[]string{"missing a", "x", "y", "missing b"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// every rotation of the input gives the same order
+			for shift := range test.errorList {
+				errorList := append(append([]error{}, test.errorList[shift:]...), test.errorList[:shift]...)
+				sortErrors(errorList)
+				var got []string
+				for _, err := range errorList {
+					got = append(got, err.Error())
+				}
+				if !reflect.DeepEqual(got, test.want) {
+					t.Errorf("shift %d: got %q, want %q", shift, got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateOutputIsDeterministic(t *testing.T) {
+	resDir := writeGreetings(t, map[string]string{
+		"values":    "Hello %1$s",
+		"values-pl": "Cześć\n%2$s",
+		"values-de": "Hallo 'du'",
+		"values-fr": "Salut",
+	})
+	first := Validate(resDir, "", "strings.xml", Options{})
+	if len(first) == 0 {
+		t.Fatal("expected validation errors")
+	}
+	for i := 0; i < 5; i++ {
+		if again := Validate(resDir, "", "strings.xml", Options{}); !reflect.DeepEqual(again, first) {
+			t.Fatalf("got %v, want %v", again, first)
+		}
+	}
+}
+
+func TestRulesCompareTheCompiledValues(t *testing.T) {
+	resDir := t.TempDir()
+	write := func(dir, content string) {
+		if err := os.MkdirAll(filepath.Join(resDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(resDir, dir, "strings.xml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("values", `<resources>
+    <string name="unquoted_space">Hello</string>
+    <string name="quoted_space">Hello</string>
+    <string name="kept_space">"Name: "</string>
+    <string name="escaped">Say "hi"</string>
+    <string name="cdata_placeholder"><![CDATA[<b>%1$s</b>]]></string>
+    <string name="cdata_missing_placeholder"><![CDATA[<b>%1$s</b>]]></string>
+</resources>
+`)
+	write("values-de", `<resources>
+    <string name="unquoted_space">  Hallo   </string>
+    <string name="quoted_space">"Hallo "</string>
+    <string name="kept_space">"Name: "</string>
+    <string name="escaped">Sag \"hallo\"</string>
+    <string name="cdata_placeholder"><![CDATA[ "<b>%1$s</b>" ]]></string>
+    <string name="cdata_missing_placeholder"><![CDATA[<b>Name</b>]]></string>
+</resources>
+`)
+	errorList := Validate(resDir, "", "strings.xml", Options{})
+
+	var got []string
+	for _, err := range errorList {
+		if ve, ok := err.(*ValidationError); ok && compiledValueRules[ve.Rule] {
+			got = append(got, ve.Key+" "+ve.Rule)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"cdata_missing_placeholder " + RulePositionalPlaceholders, "quoted_space " + RuleWhitespace}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got the errors %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matches two or more spaces between words.
+var doubleSpaceRegex *regexp.Regexp = regexp.MustCompile("\\S {2,}\\S")
+
+// Returns the value without the enclosing double quotes (which make aapt keep the whitespace).
+func unquoted(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\"") {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func hasLeadingSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return len(s) > 0 && unicode.IsSpace(r)
+}
+
+func hasTrailingSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return len(s) > 0 && unicode.IsSpace(r)
+}
+
+// Validates that the `validatedElemString` does not introduce leading or trailing whitespace,
+// or double spaces, which are not present in the `baseElemString`. The values are expected as aapt compiles them,
+// so only the whitespace kept by the double quotes (e.g. `" Name"`) is compared.
+func validateWhitespace(base, target string) error {
+	if len(strings.TrimSpace(target)) == 0 {
+		// Reported by the "empty-translation" rule.
+		return nil
+	}
+	var problems []string
+	if hasLeadingSpace(target) && !hasLeadingSpace(base) {
+		problems = append(problems, "leading whitespace")
+	}
+	if hasTrailingSpace(target) && !hasTrailingSpace(base) {
+		problems = append(problems, "trailing whitespace")
+	}
+	if doubleSpaceRegex.MatchString(target) && !doubleSpaceRegex.MatchString(base) {
+		problems = append(problems, "double spaces")
+	}
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("The target string has %s, unlike the base string", strings.Join(problems, " and ")))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/armatys/android-tools/strings/resources"
+	"sort"
+	"strings"
+)
+
+// The namespace of the xliff elements used in Android string resources.
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+// An `<xliff:g id="...">` element found in a string value.
+type xliffPlaceholder struct {
+	Id      string
+	Content string
+}
+
+func isXliffElement(name xml.Name) bool {
+	return name.Local == "g" && (name.Space == "xliff" || name.Space == xliffNamespace)
+}
+
+// Finds the `<xliff:g>` elements in the raw (inner XML) value.
+func parseXliffPlaceholders(rawValue string) ([]xliffPlaceholder, error) {
+	var placeholders []xliffPlaceholder
+	var current *xliffPlaceholder
+	err := resources.DecodeRawValue(rawValue, func(token xml.Token) {
+		switch t := token.(type) {
+		case xml.StartElement:
+			if isXliffElement(t.Name) {
+				current = &xliffPlaceholder{}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "id" {
+						current.Id = attr.Value
+					}
+				}
+			}
+		case xml.EndElement:
+			if isXliffElement(t.Name) && current != nil {
+				placeholders = append(placeholders, *current)
+				current = nil
+			}
+		case xml.CharData:
+			if current != nil {
+				current.Content += string(t)
+			}
+		}
+	})
+	return placeholders, err
+}
+
+// Validates that the `validatedElemString` has the same `<xliff:g>` elements (by id) as the `baseElemString`,
+// and that the placeholders inside them are preserved. Both arguments are expected to be raw (inner XML) values.
+func validateXliffPlaceholders(baseElemString, validatedElemString string) error {
+	basePlaceholders, err := parseXliffPlaceholders(baseElemString)
+	if err != nil {
+		return nil
+	}
+	targetPlaceholders, err := parseXliffPlaceholders(validatedElemString)
+	if err != nil {
+		return err
+	}
+	if len(basePlaceholders) == 0 && len(targetPlaceholders) == 0 {
+		return nil
+	}
+
+	targetById := make(map[string]xliffPlaceholder)
+	for _, p := range targetPlaceholders {
+		targetById[p.Id] = p
+	}
+	baseIds := make(map[string]bool)
+	var missing []string
+	for _, base := range basePlaceholders {
+		baseIds[base.Id] = true
+		target, ok := targetById[base.Id]
+		if !ok {
+			missing = append(missing, base.Id)
+			continue
+		}
+		basePlaceholder := strings.Join(placeholdersIn(base.Content), "")
+		targetPlaceholder := strings.Join(placeholdersIn(target.Content), "")
+		if basePlaceholder != targetPlaceholder {
+			return errors.New(fmt.Sprintf("The target xliff:g element '%s' contains '%s', while it probably should contain '%s'", base.Id, targetPlaceholder, basePlaceholder))
+		}
+	}
+	var extra []string
+	for _, target := range targetPlaceholders {
+		if !baseIds[target.Id] {
+			extra = append(extra, target.Id)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	if len(missing) > 0 {
+		return errors.New(fmt.Sprintf("The target string is missing the xliff:g element(s) with id: %s", strings.Join(missing, ", ")))
+	}
+	if len(extra) > 0 {
+		return errors.New(fmt.Sprintf("The target string has unexpected xliff:g element(s) with id: %s", strings.Join(extra, ", ")))
+	}
+	return nil
+}
+
+// Returns the simple and positional placeholders found in `s`.
+func placeholdersIn(s string) []string {
+	s = withoutEscapedPercents(s)
+	var placeholders []string
+	for _, match := range PositionalPlaceholderRegex.FindAllString(s, -1) {
+		placeholders = append(placeholders, match)
+	}
+	for _, match := range SimplePlaceholderRegex.FindAllString(s, -1) {
+		placeholders = append(placeholders, match)
+	}
+	return placeholders
+}